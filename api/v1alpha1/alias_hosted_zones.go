@@ -0,0 +1,131 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"strings"
+)
+
+// cloudFrontHostedZoneID is the hosted zone ID of all CloudFront distributions.
+const cloudFrontHostedZoneID = "Z2FDTNDATAQYW2"
+
+// Hosted zone IDs of the AWS endpoints per region.
+// See https://docs.aws.amazon.com/general/latest/gr/aws-service-information.html
+var (
+	// elbHostedZoneIDs are for Classic and Application Load Balancers.
+	elbHostedZoneIDs = map[string]string{
+		"us-east-1":      "Z35SXDOTRQ7X7K",
+		"us-east-2":      "Z3AADJGX6KTTL2",
+		"us-west-1":      "Z368ELLRRE2KJ0",
+		"us-west-2":      "Z1H1FL5HABSF5",
+		"ca-central-1":   "ZQSVJUPU6J1EY",
+		"ap-east-1":      "Z3DQVH9N71FHZ0",
+		"ap-south-1":     "ZP97RAFLXTNZK",
+		"ap-northeast-1": "Z14GRHDCWA56QT",
+		"ap-northeast-2": "ZWKZPGTI48KDX",
+		"ap-northeast-3": "Z5LXEXXYW11ES",
+		"ap-southeast-1": "Z1LMS91P8CMLE5",
+		"ap-southeast-2": "Z1GM3OXH4ZPM65",
+		"eu-central-1":   "Z215JYRZR1TBD5",
+		"eu-west-1":      "Z32O12XQLNTSW2",
+		"eu-west-2":      "ZHURV8PSTC4K8",
+		"eu-west-3":      "Z3Q77PNBQS71R4",
+		"eu-north-1":     "Z23TAZ7KDCMWQ2",
+		"eu-south-1":     "Z3ULH7SSC9OV64",
+		"sa-east-1":      "Z2P70J7HTTTPLU",
+		"me-south-1":     "ZS929ML54UICD",
+		"af-south-1":     "Z268VQBMOI5EKX",
+	}
+
+	// nlbHostedZoneIDs are for Network Load Balancers.
+	nlbHostedZoneIDs = map[string]string{
+		"us-east-1":      "Z26RNL4JYFTOTI",
+		"us-east-2":      "ZLMOA37VPKANP",
+		"us-west-1":      "Z24FKFUX50B4VW",
+		"us-west-2":      "Z18D5FSROUN65G",
+		"ca-central-1":   "Z2EPGBW3API2WT",
+		"ap-east-1":      "Z12Y7K3UBGUAD1",
+		"ap-south-1":     "ZVDDRBQ08TROA",
+		"ap-northeast-1": "Z31USIVHYNEOWT",
+		"ap-northeast-2": "ZIBE1TIR4HY56",
+		"ap-northeast-3": "Z1GWIQ4HH19I5X",
+		"ap-southeast-1": "ZKVM4W9LS7TM",
+		"ap-southeast-2": "ZCT6FZBF4DROD",
+		"eu-central-1":   "Z3F0SRJ5LGBH90",
+		"eu-west-1":      "Z2IFOLAFXWLO4F",
+		"eu-west-2":      "ZD4D7Y8KGAS4G",
+		"eu-west-3":      "Z1CMS0P5QUZ6D5",
+		"eu-north-1":     "Z1UDT6IFJ4EJM",
+		"eu-south-1":     "Z23146JA1KNAFP",
+		"sa-east-1":      "ZTK26PT1VY4CU",
+		"me-south-1":     "Z3QSRYVP46NYYV",
+		"af-south-1":     "Z203XCE67M25HM",
+	}
+
+	// s3WebsiteHostedZoneIDs are for S3 website endpoints.
+	s3WebsiteHostedZoneIDs = map[string]string{
+		"us-east-1":      "Z3AQBSTGFYJSTF",
+		"us-east-2":      "Z2O1EMRO9K5GLX",
+		"us-west-1":      "Z2F56UZL2M1ACD",
+		"us-west-2":      "Z3BJ6K6RIION7M",
+		"ca-central-1":   "Z1QDHH18159H29",
+		"ap-south-1":     "Z11RGJOFQNVJUP",
+		"ap-northeast-1": "Z2M4EHUR26P7ZW",
+		"ap-northeast-2": "Z3W03O7B5YMIYP",
+		"ap-northeast-3": "Z2YQB5RD63NC85",
+		"ap-southeast-1": "Z3O0J2DXBE1FTB",
+		"ap-southeast-2": "Z1WCIGYICN2BYD",
+		"eu-central-1":   "Z21DNDUVLTQW6Q",
+		"eu-west-1":      "Z1BKCTXD74EZPE",
+		"eu-west-2":      "Z3GKZC51ZF0DB4",
+		"eu-west-3":      "Z3R1K369G5AVDG",
+		"eu-north-1":     "Z3BAZG2TWCNX0D",
+		"sa-east-1":      "Z7KQH4QJS55SO",
+	}
+)
+
+// canonicalHostedZoneID returns the hosted zone ID of a well-known AWS endpoint,
+// or an empty string if the hostname is not one of them.
+func canonicalHostedZoneID(hostname string) string {
+	hostname = strings.ToLower(strings.TrimSuffix(hostname, "."))
+
+	if strings.HasSuffix(hostname, ".cloudfront.net") {
+		return cloudFrontHostedZoneID
+	}
+
+	labels := strings.Split(hostname, ".")
+	n := len(labels)
+	if n < 4 || labels[n-1] != "com" || labels[n-2] != "amazonaws" {
+		return ""
+	}
+
+	switch {
+	// <name>.<region>.elb.amazonaws.com
+	case labels[n-3] == "elb":
+		return elbHostedZoneIDs[labels[n-4]]
+	// <name>.elb.<region>.amazonaws.com
+	case labels[n-4] == "elb":
+		return nlbHostedZoneIDs[labels[n-3]]
+	// <bucket>.s3-website-<region>.amazonaws.com
+	case strings.HasPrefix(labels[n-3], "s3-website-"):
+		return s3WebsiteHostedZoneIDs[strings.TrimPrefix(labels[n-3], "s3-website-")]
+	// <bucket>.s3-website.<region>.amazonaws.com
+	case labels[n-4] == "s3-website":
+		return s3WebsiteHostedZoneIDs[labels[n-3]]
+	}
+	return ""
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CidrCollectionSpec defines the desired state of CidrCollection
+type CidrCollectionSpec struct {
+	// ProviderRef is the Provider whose credentials manage the collection.
+	ProviderRef string `json:"providerRef"`
+
+	// CollectionName is the name of the collection in the provider.
+	// It defaults to the name of the CidrCollection.
+	// +optional
+	// +kubebuilder:validation:MaxLength=64
+	// +kubebuilder:validation:Pattern=`^[0-9A-Za-z_\-]+$`
+	CollectionName string `json:"collectionName,omitempty"`
+
+	// Locations are the named groups of CIDR blocks which ResourceRecords route by.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Locations []CidrLocation `json:"locations,omitempty"`
+}
+
+// CidrLocation is a named group of CIDR blocks.
+type CidrLocation struct {
+	// +kubebuilder:validation:MaxLength=16
+	// +kubebuilder:validation:Pattern=`^[0-9A-Za-z_\-]+$`
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:MinItems=1
+	CidrBlocks []string `json:"cidrBlocks"`
+}
+
+// CollectionName returns the name of the collection in the provider.
+func (c *CidrCollection) CollectionName() string {
+	if c.Spec.CollectionName != "" {
+		return c.Spec.CollectionName
+	}
+	return c.Name
+}
+
+// CidrCollectionStatus defines the observed state of CidrCollection
+type CidrCollectionStatus struct {
+	// Conditions represent the latest available observations of the CidrCollection.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// ObservedGeneration is the generation most recently observed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// CollectionID is the ID of the collection in the provider.
+	// +optional
+	CollectionID string `json:"collectionID,omitempty"`
+
+	// Version is the version of the collection in the provider, which is
+	// incremented by every change.
+	// +optional
+	Version int64 `json:"version,omitempty"`
+}
+
+const (
+	// CidrCollectionConditionReady indicates the collection is in the desired state.
+	CidrCollectionConditionReady = "Ready"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Collection ID",type=string,JSONPath=`.status.collectionID`
+//+kubebuilder:printcolumn:name="Version",type=integer,JSONPath=`.status.version`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// CidrCollection is the Schema for the cidrcollections API
+type CidrCollection struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CidrCollectionSpec   `json:"spec,omitempty"`
+	Status CidrCollectionStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// CidrCollectionList contains a list of CidrCollection
+type CidrCollectionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CidrCollection `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CidrCollection{}, &CidrCollectionList{})
+}
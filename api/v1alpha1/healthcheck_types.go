@@ -0,0 +1,206 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HealthCheckSpec defines the desired state of HealthCheck
+type HealthCheckSpec struct {
+	// ProviderRef is the Provider whose credentials manage the health check.
+	ProviderRef string `json:"providerRef"`
+
+	// Type is the kind of the check. Type, RequestInterval and MeasureLatency
+	// can not be changed after the health check is created.
+	Type HealthCheckType `json:"type"`
+
+	// IPAddress is the address of the checked endpoint. FullyQualifiedDomainName
+	// is resolved when it is omitted.
+	// +optional
+	IPAddress string `json:"ipAddress,omitempty"`
+
+	// FullyQualifiedDomainName is the host of the checked endpoint, which is
+	// also sent in the Host header and SNI.
+	// +optional
+	// +kubebuilder:validation:MaxLength=255
+	FullyQualifiedDomainName string `json:"fullyQualifiedDomainName,omitempty"`
+
+	// Port defaults to 80 for HTTP and 443 for HTTPS checks. It is required for TCP checks.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port *int32 `json:"port,omitempty"`
+
+	// +optional
+	// +kubebuilder:validation:MaxLength=255
+	ResourcePath string `json:"resourcePath,omitempty"`
+
+	// SearchString must appear in the response body of string matching checks.
+	// +optional
+	// +kubebuilder:validation:MaxLength=255
+	SearchString string `json:"searchString,omitempty"`
+
+	// RequestInterval is the number of seconds between checks. It defaults to 30.
+	// +optional
+	// +kubebuilder:validation:Enum=10;30
+	RequestInterval *int32 `json:"requestInterval,omitempty"`
+
+	// FailureThreshold is the number of consecutive checks which change the
+	// status. It defaults to 3.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10
+	FailureThreshold *int32 `json:"failureThreshold,omitempty"`
+
+	// +optional
+	MeasureLatency bool `json:"measureLatency,omitempty"`
+
+	// EnableSNI defaults to true for HTTPS checks.
+	// +optional
+	EnableSNI *bool `json:"enableSNI,omitempty"`
+
+	// Regions are the regions of the checkers. All regions are used when it is empty.
+	// +optional
+	// +kubebuilder:validation:MinItems=3
+	Regions []string `json:"regions,omitempty"`
+
+	// Inverted reports the endpoint unhealthy when it is healthy and vice versa.
+	// +optional
+	Inverted bool `json:"inverted,omitempty"`
+
+	// Disabled reports the endpoint healthy without checking it.
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+
+	// ChildHealthChecks are the names of the HealthChecks a CALCULATED check combines.
+	// +optional
+	// +kubebuilder:validation:MaxItems=256
+	ChildHealthChecks []string `json:"childHealthChecks,omitempty"`
+
+	// HealthThreshold is the number of healthy children which make a
+	// CALCULATED check healthy. It defaults to all children.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=256
+	HealthThreshold *int32 `json:"healthThreshold,omitempty"`
+
+	// AlarmIdentifier is the CloudWatch alarm a CLOUDWATCH_METRIC check follows.
+	// +optional
+	AlarmIdentifier *AlarmIdentifier `json:"alarmIdentifier,omitempty"`
+
+	// InsufficientDataHealthStatus is the status of a CLOUDWATCH_METRIC check
+	// while the alarm has insufficient data. It defaults to LastKnownStatus.
+	// +optional
+	// +kubebuilder:validation:Enum=Healthy;Unhealthy;LastKnownStatus
+	InsufficientDataHealthStatus string `json:"insufficientDataHealthStatus,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=HTTP;HTTPS;HTTP_STR_MATCH;HTTPS_STR_MATCH;TCP;CALCULATED;CLOUDWATCH_METRIC
+type HealthCheckType string
+
+const (
+	HealthCheckHTTP             HealthCheckType = "HTTP"
+	HealthCheckHTTPS            HealthCheckType = "HTTPS"
+	HealthCheckHTTPStrMatch     HealthCheckType = "HTTP_STR_MATCH"
+	HealthCheckHTTPSStrMatch    HealthCheckType = "HTTPS_STR_MATCH"
+	HealthCheckTCP              HealthCheckType = "TCP"
+	HealthCheckCalculated       HealthCheckType = "CALCULATED"
+	HealthCheckCloudWatchMetric HealthCheckType = "CLOUDWATCH_METRIC"
+)
+
+// AlarmIdentifier identifies a CloudWatch alarm.
+type AlarmIdentifier struct {
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Pattern=`^[a-z]{2}(-[a-z]+)+-[0-9]+$`
+	Region string `json:"region"`
+}
+
+// HealthStatus is the latest result of a health check.
+type HealthStatus string
+
+const (
+	HealthStatusHealthy   HealthStatus = "Healthy"
+	HealthStatusUnhealthy HealthStatus = "Unhealthy"
+	HealthStatusUnknown   HealthStatus = "Unknown"
+)
+
+// HealthCheckStatus defines the observed state of HealthCheck
+type HealthCheckStatus struct {
+	// Conditions represent the latest available observations of the HealthCheck.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// ObservedGeneration is the generation most recently observed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// HealthCheckID is the ID of the health check in the provider.
+	// +optional
+	HealthCheckID string `json:"healthCheckID,omitempty"`
+
+	// HealthCheckVersion is the version of the health check in the provider,
+	// which is incremented by every change.
+	// +optional
+	HealthCheckVersion int64 `json:"healthCheckVersion,omitempty"`
+
+	// Health is the latest status reported by the checkers.
+	// +optional
+	Health HealthStatus `json:"health,omitempty"`
+
+	// LastCheckedTime is when the current Health was first observed.
+	// +optional
+	LastCheckedTime *metav1.Time `json:"lastCheckedTime,omitempty"`
+}
+
+const (
+	// HealthCheckConditionReady indicates the health check is in the desired state.
+	HealthCheckConditionReady = "Ready"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.type`
+//+kubebuilder:printcolumn:name="Health Check ID",type=string,JSONPath=`.status.healthCheckID`
+//+kubebuilder:printcolumn:name="Health",type=string,JSONPath=`.status.health`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// HealthCheck is the Schema for the healthchecks API
+type HealthCheck struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   HealthCheckSpec   `json:"spec,omitempty"`
+	Status HealthCheckStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// HealthCheckList contains a list of HealthCheck
+type HealthCheckList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HealthCheck `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&HealthCheck{}, &HealthCheckList{})
+}
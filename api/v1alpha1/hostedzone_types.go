@@ -0,0 +1,160 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HostedZoneSpec defines the desired state of HostedZone
+type HostedZoneSpec struct {
+	// ProviderRef is the Provider whose credentials manage the hosted zone.
+	ProviderRef string `json:"providerRef"`
+
+	// ZoneName is the domain name of the zone. It can not be changed after
+	// the zone is created.
+	// +kubebuilder:validation:MaxLength=1024
+	// +kubebuilder:validation:Pattern=`^([a-zA-Z0-9_]([a-zA-Z0-9\-_]{0,61}[a-zA-Z0-9_])?\.)*[a-zA-Z0-9_]([a-zA-Z0-9\-_]{0,61}[a-zA-Z0-9_])?\.?$`
+	ZoneName string `json:"zoneName"`
+
+	// +optional
+	// +kubebuilder:validation:MaxLength=256
+	Comment string `json:"comment,omitempty"`
+
+	// Private makes the zone a private hosted zone, which answers the queries
+	// from its VPCs only. It can not be changed after the zone is created.
+	// +optional
+	Private bool `json:"private,omitempty"`
+
+	// VPCs are the VPCs a private hosted zone is associated with. The first
+	// one is associated when the zone is created and must belong to the
+	// account of the provider.
+	// +optional
+	VPCs []VPC `json:"vpcs,omitempty"`
+
+	// DelegationSetID is the reusable delegation set whose name servers are
+	// given to a public zone when it is created.
+	// +optional
+	DelegationSetID string `json:"delegationSetID,omitempty"`
+
+	// +optional
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// Delegation writes the NS records of the zone into its parent zone.
+	// Existing NS records are only replaced when the adopt annotation is set.
+	// +optional
+	Delegation *ZoneDelegation `json:"delegation,omitempty"`
+
+	// DeletionPolicy decides whether the zone is removed from the provider
+	// when the HostedZone is deleted. Retain leaves it orphaned.
+	// +optional
+	// +kubebuilder:validation:Enum=Delete;Retain
+	// +kubebuilder:default=Delete
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// VPC identifies a VPC by its ID and region.
+type VPC struct {
+	// +kubebuilder:validation:MaxLength=1024
+	ID string `json:"id"`
+
+	// +kubebuilder:validation:Pattern=`^[a-z]{2}(-[a-z]+)+-[0-9]+$`
+	Region string `json:"region"`
+
+	// Auth is the credentials of the account which owns a VPC in another
+	// account. The account of the zone authorizes the association, which is
+	// then made with these credentials.
+	// +optional
+	Auth *AWSAuth `json:"auth,omitempty"`
+}
+
+// ZoneDelegation describes the parent zone which delegates to the zone.
+type ZoneDelegation struct {
+	// ProviderRef is the Provider of the parent zone. The zone must be a
+	// subdomain of the parent zone.
+	ProviderRef string `json:"providerRef"`
+
+	// Ttl is the TTL of the NS records. It defaults to 172800.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=2147483647
+	Ttl *int32 `json:"ttl,omitempty"`
+}
+
+// HostedZoneStatus defines the observed state of HostedZone
+type HostedZoneStatus struct {
+	// Conditions represent the latest available observations of the HostedZone.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// ObservedGeneration is the generation most recently observed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// HostedZoneID is the ID of the zone in the provider.
+	// +optional
+	HostedZoneID string `json:"hostedZoneID,omitempty"`
+
+	// NameServers are the name servers of a public zone.
+	// +optional
+	NameServers []string `json:"nameServers,omitempty"`
+
+	// DelegationProviderRef is the Provider of the parent zone the NS records
+	// were written to, so that they are removed when the delegation changes.
+	// +optional
+	DelegationProviderRef string `json:"delegationProviderRef,omitempty"`
+}
+
+const (
+	// HostedZoneConditionReady indicates the zone is in the desired state.
+	HostedZoneConditionReady = "Ready"
+	// HostedZoneConditionDelegated indicates the parent zone delegates to the zone.
+	HostedZoneConditionDelegated = "Delegated"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Zone",type=string,JSONPath=`.spec.zoneName`
+//+kubebuilder:printcolumn:name="Hosted Zone ID",type=string,JSONPath=`.status.hostedZoneID`
+//+kubebuilder:printcolumn:name="Private",type=boolean,JSONPath=`.spec.private`
+//+kubebuilder:printcolumn:name="Delegated",type=string,JSONPath=`.status.conditions[?(@.type=="Delegated")].status`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// HostedZone is the Schema for the hostedzones API
+type HostedZone struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   HostedZoneSpec   `json:"spec,omitempty"`
+	Status HostedZoneStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// HostedZoneList contains a list of HostedZone
+type HostedZoneList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HostedZone `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&HostedZone{}, &HostedZoneList{})
+}
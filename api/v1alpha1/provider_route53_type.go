@@ -1,7 +1,44 @@
 package v1alpha1
 
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
 type AWSAuth struct {
 	SecretRef *AWSAuthSecretRef `json:"secretRef,omitempty"`
+
+	// AssumeRole is the role assumed with STS to access Route53, e.g. a role
+	// in the account of the hosted zone. The role is assumed with the
+	// credentials of SecretRef when it is set, or the default credentials.
+	// +optional
+	AssumeRole *AWSAssumeRole `json:"assumeRole,omitempty"`
+}
+
+type AWSAssumeRole struct {
+	// RoleARN is the ARN of the role to assume.
+	// +kubebuilder:validation:Pattern=`^arn:aws[a-z-]*:iam::[0-9]{12}:role/.+$`
+	RoleARN string `json:"roleARN"`
+
+	// ExternalID is the external ID required by the trust policy of the role.
+	// +optional
+	ExternalID string `json:"externalID,omitempty"`
+
+	// SessionName is the name of the role session. It defaults to the
+	// namespace and the name of the Provider.
+	// +optional
+	// +kubebuilder:validation:MaxLength=64
+	// +kubebuilder:validation:Pattern=`^[\w+=,.@-]*$`
+	SessionName string `json:"sessionName,omitempty"`
+
+	// Duration is the lifetime of the role session credentials, 15 minutes
+	// by default. They are refreshed before they expire.
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// STSEndpoint overrides the endpoint of STS, e.g. a VPC endpoint or a
+	// local stand-in.
+	// +optional
+	STSEndpoint string `json:"stsEndpoint,omitempty"`
 }
 
 type AWSAuthSecretRef struct {
@@ -13,13 +50,40 @@ type AWSAuthSecretRef struct {
 }
 
 type Route53Provider struct {
-	HostedZoneID string `json:"hostedZoneID"`
+	// HostedZoneID is the ID of the hosted zone. The zone is looked up by
+	// HostedZoneName when it is empty.
+	// +optional
+	HostedZoneID string `json:"hostedZoneID,omitempty"`
 
 	HostedZoneName string `json:"hostedZoneName"`
 
+	// ZoneType selects the public or the private hosted zone of the name when
+	// the zone is looked up by name.
+	// +optional
+	// +kubebuilder:validation:Enum=Public;Private
+	ZoneType ZoneType `json:"zoneType,omitempty"`
+
+	// VPCHint is the ID of a VPC the private hosted zone is associated with.
+	// It selects the zone among the private hosted zones of the same name when
+	// the zone is looked up by name.
+	// +optional
+	VPCHint string `json:"vpcHint,omitempty"`
+
 	// +optional
 	Region string `json:"region"`
 
 	// +optional
 	Auth AWSAuth `json:"auth"`
+
+	// VPCs are the VPCs a private hosted zone is associated with. The
+	// associations are left unmanaged when it is empty.
+	// +optional
+	VPCs []VPC `json:"vpcs,omitempty"`
 }
+
+type ZoneType string
+
+const (
+	ZoneTypePublic  ZoneType = "Public"
+	ZoneTypePrivate ZoneType = "Private"
+)
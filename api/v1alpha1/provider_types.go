@@ -25,17 +25,62 @@ import (
 
 // ProviderSpec defines the desired state of Provider
 type ProviderSpec struct {
-	Route53 Route53Provider `json:"route53,omitempty"`
+	// +optional
+	Route53 *Route53Provider `json:"route53,omitempty"`
+
+	// DefaultTtl is the TTL given to the ResourceRecords of this Provider
+	// which do not specify one.
+	// +optional
+	// +nullable
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=2147483647
+	DefaultTtl *int32 `json:"defaultTtl,omitempty"`
 }
 
 // ProviderStatus defines the observed state of Provider
 type ProviderStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
+
+	// Conditions represent the latest available observations of the Provider.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// ObservedGeneration is the generation most recently observed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// PrivateZone is true when the hosted zone is a private hosted zone.
+	// +optional
+	PrivateZone bool `json:"privateZone,omitempty"`
+
+	// HostedZoneID is the ID of the hosted zone found for the observed
+	// generation. It caches the zone looked up by name.
+	// +optional
+	HostedZoneID string `json:"hostedZoneID,omitempty"`
 }
 
+const (
+	// ProviderConditionReady indicates the provider can be used by ResourceRecords.
+	ProviderConditionReady = "Ready"
+	// ProviderConditionCredentialsValid indicates the credentials are accepted by the provider.
+	ProviderConditionCredentialsValid = "CredentialsValid"
+	// ProviderConditionHostedZoneValid indicates the hosted zone exists and matches its name.
+	ProviderConditionHostedZoneValid = "HostedZoneValid"
+	// ProviderConditionVPCsAssociated indicates the private hosted zone is associated with the declared VPCs.
+	ProviderConditionVPCsAssociated = "VPCsAssociated"
+)
+
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Zone",type=string,JSONPath=`.spec.route53.hostedZoneName`
+//+kubebuilder:printcolumn:name="Zone ID",type=string,JSONPath=`.status.hostedZoneID`,priority=1
+//+kubebuilder:printcolumn:name="Private",type=boolean,JSONPath=`.status.privateZone`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // Provider is the Schema for the providers API
 type Provider struct {
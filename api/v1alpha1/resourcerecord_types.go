@@ -28,12 +28,16 @@ type ResourceRecordSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 
+	// Class is the type of the records. It can not be changed.
 	// +kubebuilder:validation:Enum=A;NS;AAAA;MX;CNAME;SRV;TXT
 	Class string `json:"class"`
 
+	// Ttl is required for regular records and must be omitted for alias records.
+	// +optional
+	// +nullable
 	// +kubebuilder:validation:Minimum=0
 	// +kubebuilder:validation:Maximum=2147483647
-	Ttl int32 `json:"ttl"`
+	Ttl *int32 `json:"ttl,omitempty"`
 
 	// +optional
 	// +nullable
@@ -43,11 +47,18 @@ type ResourceRecordSpec struct {
 
 	OwnerRef string `json:"ownerRef"`
 
+	// ProviderRef is the name of the Provider of the zone the records are
+	// written to. It can not be changed.
 	ProviderRef string `json:"providerRef"`
 
 	// +optional
 	Rdata string `json:"rdata,omitempty"`
 
+	// Rdatas are the values of a record set with several values,
+	// e.g. round-robin A records or multiple TXT records. It is used instead of Rdata.
+	// +optional
+	Rdatas []string `json:"rdatas,omitempty"`
+
 	// +optional
 	// +kubebuilder:default=false
 	IsAlias bool `json:"isAlias,omitempty"`
@@ -55,9 +66,92 @@ type ResourceRecordSpec struct {
 	// +optional
 	AliasTarget AliasTarget `json:"aliasTarget,omitempty"`
 
+	// Id tells apart the records of a routing policy with the same name and
+	// class. It can not be changed.
 	// +optional
 	// +nullable
 	Id *string `json:"id,omitempty"`
+
+	// Failover makes the record the primary or secondary of an active-passive
+	// failover pair. The records of the pair share their owner and differ by Id.
+	// +optional
+	Failover *Failover `json:"failover,omitempty"`
+
+	// Region makes the record a latency record which answers the queries
+	// with the lowest latency to the AWS region. The latency records of a
+	// name share their owner and differ by Id.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[a-z]{2}(-[a-z]+)+-[0-9]+$`
+	Region string `json:"region,omitempty"`
+
+	// GeoLocation makes the record a geolocation record which answers the
+	// queries from the location. The geolocation records of a name share their
+	// owner and differ by Id, and one of them must be the default location.
+	// +optional
+	GeoLocation *GeoLocation `json:"geoLocation,omitempty"`
+
+	// GeoProximity makes the record a geoproximity record which answers the
+	// queries closest to an AWS region or coordinates. The geoproximity records
+	// of a name share their owner and differ by Id.
+	// +optional
+	GeoProximity *GeoProximity `json:"geoProximity,omitempty"`
+
+	// MultiValueAnswer makes the record one of the multivalue answer records
+	// of a name, which are answered together while they are healthy. The
+	// records share their owner and differ by Id, and each has one value.
+	// +optional
+	MultiValueAnswer *MultiValueAnswer `json:"multiValueAnswer,omitempty"`
+
+	// CidrRouting makes the record an IP-based record which answers the
+	// queries from the CIDR blocks of a location in a CidrCollection. The
+	// IP-based records of a name share their owner and differ by Id.
+	// +optional
+	CidrRouting *CidrRouting `json:"cidrRouting,omitempty"`
+
+	// HealthCheckRef is the name of the HealthCheck in the namespace which
+	// decides whether the record is healthy. It is used instead of the
+	// healthCheckID of the routing policy.
+	// +optional
+	HealthCheckRef string `json:"healthCheckRef,omitempty"`
+
+	// DeletionPolicy decides whether the records are removed from the provider
+	// when the ResourceRecord is deleted. Retain leaves them orphaned.
+	// +optional
+	// +kubebuilder:validation:Enum=Delete;Retain
+	// +kubebuilder:default=Delete
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// AdoptAnnotation lets the controller claim existing records which are not
+// owned by any ResourceRecord when it is set to "true".
+const AdoptAnnotation = "dns.ch1aki.github.io/adopt"
+
+type DeletionPolicy string
+
+const (
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+)
+
+// Values returns the rdata values of the record set.
+func (s ResourceRecordSpec) Values() []string {
+	if len(s.Rdatas) != 0 {
+		return s.Rdatas
+	}
+	if s.Rdata != "" {
+		return []string{s.Rdata}
+	}
+	return nil
+}
+
+// SetIdentifier returns the Id which tells apart the records of the routing
+// policy. An Id without a routing policy, which older versions accepted, is
+// ignored.
+func (s ResourceRecordSpec) SetIdentifier() *string {
+	if len(routingPolicies(s)) == 0 {
+		return nil
+	}
+	return s.Id
 }
 
 type AliasTarget struct {
@@ -70,14 +164,149 @@ type AliasTarget struct {
 	HostedZoneID string `json:"hostedZoneID,omitempty"`
 }
 
+// +kubebuilder:validation:Enum=PRIMARY;SECONDARY
+type FailoverType string
+
+const (
+	FailoverPrimary   FailoverType = "PRIMARY"
+	FailoverSecondary FailoverType = "SECONDARY"
+)
+
+type Failover struct {
+	Type FailoverType `json:"type"`
+
+	// HealthCheckID is the ID of the health check which decides whether the
+	// record is healthy. It is usually required for the primary.
+	// +optional
+	HealthCheckID string `json:"healthCheckID,omitempty"`
+}
+
+type MultiValueAnswer struct {
+	// HealthCheckID is the ID of the health check which decides whether the
+	// record is answered.
+	// +optional
+	HealthCheckID string `json:"healthCheckID,omitempty"`
+}
+
+type CidrRouting struct {
+	// CollectionRef is the name of the CidrCollection in the namespace.
+	CollectionRef string `json:"collectionRef"`
+
+	// LocationName is a location of the collection, or "*" for the queries
+	// from the other CIDR blocks.
+	// +kubebuilder:validation:MaxLength=16
+	// +kubebuilder:validation:Pattern=`^([0-9A-Za-z_\-]+|\*)$`
+	LocationName string `json:"locationName"`
+}
+
+// GeoLocation is a continent, a country or a subdivision of a country.
+// The CountryCode "*" is the default location which answers the queries from
+// the other locations.
+type GeoLocation struct {
+	// +optional
+	// +kubebuilder:validation:Enum=AF;AN;AS;EU;NA;OC;SA
+	ContinentCode string `json:"continentCode,omitempty"`
+
+	// CountryCode is a two-letter ISO 3166-1 code or "*".
+	// +optional
+	// +kubebuilder:validation:Pattern=`^([A-Z]{2}|\*)$`
+	CountryCode string `json:"countryCode,omitempty"`
+
+	// SubdivisionCode is a subdivision of the country, e.g. a state of the United States.
+	// +optional
+	SubdivisionCode string `json:"subdivisionCode,omitempty"`
+}
+
+// GeoLocationDefault is the CountryCode of the default location.
+const GeoLocationDefault = "*"
+
+// IsDefault reports whether the location is the default location.
+func (g GeoLocation) IsDefault() bool {
+	return g.CountryCode == GeoLocationDefault
+}
+
+// GeoProximity is the location of a geoproximity record, either an AWS region
+// or coordinates.
+type GeoProximity struct {
+	// +optional
+	AWSRegion string `json:"awsRegion,omitempty"`
+
+	// +optional
+	Coordinates *Coordinates `json:"coordinates,omitempty"`
+
+	// Bias expands (positive) or shrinks (negative) the area from which
+	// queries are routed to the record.
+	// +optional
+	// +kubebuilder:validation:Minimum=-99
+	// +kubebuilder:validation:Maximum=99
+	Bias *int32 `json:"bias,omitempty"`
+}
+
+// Coordinates are a latitude and a longitude in degrees with up to two decimal places.
+type Coordinates struct {
+	// +kubebuilder:validation:Pattern=`^[-+]?[0-9]{1,2}(\.[0-9]{0,2})?$`
+	Latitude string `json:"latitude"`
+
+	// +kubebuilder:validation:Pattern=`^[-+]?[0-9]{1,3}(\.[0-9]{0,2})?$`
+	Longitude string `json:"longitude"`
+}
+
 // ResourceRecordStatus defines the observed state of ResourceRecord
 type ResourceRecordStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
+
+	// Conditions represent the latest available observations of the ResourceRecord.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// ObservedGeneration is the generation most recently observed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// FQDNs are the names of the records managed by this ResourceRecord.
+	// +optional
+	FQDNs []string `json:"fqdns,omitempty"`
+
+	// ChangeID is the ID of the last change submitted to the provider.
+	// +optional
+	ChangeID string `json:"changeID,omitempty"`
+
+	// ChangeSubmitTime is the time the last change was submitted to the provider.
+	// +optional
+	ChangeSubmitTime *metav1.Time `json:"changeSubmitTime,omitempty"`
+
+	// PropagationDuration is the time the last change took to propagate to
+	// the name servers of the provider.
+	// +optional
+	PropagationDuration *metav1.Duration `json:"propagationDuration,omitempty"`
+
+	// LastSyncTime is the last time the records were successfully synced.
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
 }
 
+const (
+	// ResourceRecordConditionReady indicates the records are synced and the last change has propagated.
+	ResourceRecordConditionReady = "Ready"
+	// ResourceRecordConditionSynced indicates the last sync with the provider succeeded.
+	ResourceRecordConditionSynced = "Synced"
+	// ResourceRecordConditionPropagated indicates the last change is served by all name servers of the provider.
+	ResourceRecordConditionPropagated = "Propagated"
+)
+
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Class",type=string,JSONPath=`.spec.class`
+//+kubebuilder:printcolumn:name="Owner",type=string,JSONPath=`.spec.ownerRef`
+//+kubebuilder:printcolumn:name="Provider",type=string,JSONPath=`.spec.providerRef`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
+//+kubebuilder:printcolumn:name="Propagated",type=string,JSONPath=`.status.conditions[?(@.type=="Propagated")].status`,priority=1
+//+kubebuilder:printcolumn:name="Last Sync",type=date,JSONPath=`.status.lastSyncTime`,priority=1
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // ResourceRecord is the Schema for the resourcerecords API
 type ResourceRecord struct {
@@ -17,8 +17,20 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"context"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
@@ -26,7 +38,15 @@ import (
 // log is for logging in this package.
 var resourcerecordlog = logf.Log.WithName("resourcerecord-resource")
 
+// resourcerecordClient is used by the defaulter to look up the Provider.
+var resourcerecordClient client.Reader
+
+// DefaultTtl is the TTL given to records when neither the ResourceRecord nor
+// its Provider specify one.
+const DefaultTtl int32 = 300
+
 func (r *ResourceRecord) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	resourcerecordClient = mgr.GetClient()
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
 		Complete()
@@ -42,7 +62,45 @@ var _ webhook.Defaulter = &ResourceRecord{}
 func (r *ResourceRecord) Default() {
 	resourcerecordlog.Info("default", "name", r.Name)
 
-	// TODO(user): fill in your defaulting logic.
+	if r.Spec.IsAlias {
+		// alias records take the TTL of their target, older versions required one
+		r.Spec.Ttl = nil
+		if r.Spec.AliasTarget.Record != "" {
+			r.Spec.AliasTarget.Record = toFQDN(strings.ToLower(r.Spec.AliasTarget.Record))
+		}
+		if r.Spec.AliasTarget.HostedZoneID == "" {
+			r.Spec.AliasTarget.HostedZoneID = canonicalHostedZoneID(r.Spec.AliasTarget.Record)
+		}
+		return
+	}
+
+	if r.Spec.Ttl == nil {
+		ttl := r.defaultTtl()
+		r.Spec.Ttl = &ttl
+	}
+	if r.Spec.Rdata != "" {
+		r.Spec.Rdata = defaultRdata(r.Spec.Class, r.Spec.Rdata)
+	}
+	for i, v := range r.Spec.Rdatas {
+		r.Spec.Rdatas[i] = defaultRdata(r.Spec.Class, v)
+	}
+}
+
+// defaultTtl returns the default TTL of the Provider, falling back to DefaultTtl.
+func (r *ResourceRecord) defaultTtl() int32 {
+	if resourcerecordClient == nil || r.Spec.ProviderRef == "" {
+		return DefaultTtl
+	}
+	var p Provider
+	err := resourcerecordClient.Get(context.TODO(), client.ObjectKey{Namespace: r.Namespace, Name: r.Spec.ProviderRef}, &p)
+	if err != nil {
+		resourcerecordlog.Info("unable to get Provider, use the default ttl", "name", r.Namespace+"/"+r.Spec.ProviderRef, "error", err.Error())
+		return DefaultTtl
+	}
+	if p.Spec.DefaultTtl == nil {
+		return DefaultTtl
+	}
+	return *p.Spec.DefaultTtl
 }
 
 // TODO(user): change verbs to "verbs=create;update;delete" if you want to enable deletion validation.
@@ -54,16 +112,26 @@ var _ webhook.Validator = &ResourceRecord{}
 func (r *ResourceRecord) ValidateCreate() error {
 	resourcerecordlog.Info("validate create", "name", r.Name)
 
-	// TODO(user): fill in your validation logic upon object creation.
-	return nil
+	return r.validate(nil)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *ResourceRecord) ValidateUpdate(old runtime.Object) error {
 	resourcerecordlog.Info("validate update", "name", r.Name)
 
-	// TODO(user): fill in your validation logic upon object update.
-	return nil
+	oldRecord, ok := old.(*ResourceRecord)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected a ResourceRecord but got a %T", old))
+	}
+	if allErrs := validateResourceRecordSpecUpdate(r.Spec, oldRecord.Spec, field.NewPath("spec")); len(allErrs) != 0 {
+		return apierrors.NewInvalid(GroupVersion.WithKind("ResourceRecord").GroupKind(), r.Name, allErrs)
+	}
+	// updates of the metadata, e.g. the removal of the finalizer while the
+	// siblings are deleted too, must not be blocked by the siblings
+	if !r.DeletionTimestamp.IsZero() || equality.Semantic.DeepEqual(oldRecord.Spec, r.Spec) {
+		return nil
+	}
+	return r.validate(&oldRecord.Spec)
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
@@ -73,3 +141,388 @@ func (r *ResourceRecord) ValidateDelete() error {
 	// TODO(user): fill in your validation logic upon object deletion.
 	return nil
 }
+
+const (
+	// maxTXTStringLength is the length limit of a character-string in a TXT record.
+	maxTXTStringLength = 255
+	// maxRdataLength is the length limit of a record value in Route53.
+	maxRdataLength = 4000
+	// maxIdLength is the length limit of a set identifier in Route53.
+	maxIdLength = 128
+)
+
+var hostnameRegexp = regexp.MustCompile(`^(\*|[A-Za-z0-9_]([A-Za-z0-9_-]{0,61}[A-Za-z0-9_])?)(\.[A-Za-z0-9_]([A-Za-z0-9_-]{0,61}[A-Za-z0-9_])?)*\.?$`)
+
+// defaultRdata rewrites a value into the form returned by Route53, so that it
+// compares equal to the live record.
+func defaultRdata(class, rdata string) string {
+	switch class {
+	case "CNAME", "NS":
+		return toFQDN(rdata)
+	case "MX":
+		if fields := strings.Fields(rdata); len(fields) == 2 {
+			return fields[0] + " " + toFQDN(fields[1])
+		}
+	case "SRV":
+		if fields := strings.Fields(rdata); len(fields) == 4 {
+			return strings.Join(append(fields[:3], toFQDN(fields[3])), " ")
+		}
+	case "TXT":
+		if !strings.HasPrefix(strings.TrimSpace(rdata), `"`) {
+			return quoteTXT(rdata)
+		}
+	}
+	return rdata
+}
+
+func toFQDN(name string) string {
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "."
+}
+
+// quoteTXT quotes a raw TXT value, splitting it into character-strings of
+// at most maxTXTStringLength characters.
+func quoteTXT(value string) string {
+	var strs []string
+	for len(value) > maxTXTStringLength {
+		strs = append(strs, value[:maxTXTStringLength])
+		value = value[maxTXTStringLength:]
+	}
+	strs = append(strs, value)
+
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	for i, str := range strs {
+		strs[i] = `"` + escaper.Replace(str) + `"`
+	}
+	return strings.Join(strs, " ")
+}
+
+// validate checks the record, which replaces a record with the old spec on update.
+func (r *ResourceRecord) validate(old *ResourceRecordSpec) error {
+	allErrs := validateResourceRecordSpec(r.Spec, old, field.NewPath("spec"))
+	if resourcerecordClient != nil {
+		siblings, err := r.siblings(resourcerecordClient)
+		if err != nil {
+			return apierrors.NewInternalError(err)
+		}
+		allErrs = append(allErrs, validateSiblings(r, siblings, field.NewPath("spec"))...)
+	}
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind("ResourceRecord").GroupKind(), r.Name, allErrs)
+}
+
+// validateResourceRecordSpecUpdate rejects changes to the provider, the class
+// and the id, which identify the zone, the record set and its ownership record
+// in the provider. The set applied before would be left behind, so the record
+// is replaced instead.
+func validateResourceRecordSpecUpdate(spec, old ResourceRecordSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, apivalidation.ValidateImmutableField(spec.ProviderRef, old.ProviderRef, fldPath.Child("providerRef"))...)
+	allErrs = append(allErrs, apivalidation.ValidateImmutableField(spec.Class, old.Class, fldPath.Child("class"))...)
+	allErrs = append(allErrs, apivalidation.ValidateImmutableField(spec.Id, old.Id, fldPath.Child("id"))...)
+	return allErrs
+}
+
+// validateResourceRecordSpec checks spec, which replaces old on update. Older
+// versions accepted an id without a routing policy, so it is only rejected when
+// the update removes the routing policy.
+func validateResourceRecordSpec(spec ResourceRecordSpec, old *ResourceRecordSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	policies := routingPolicies(spec)
+	if 1 < len(policies) {
+		allErrs = append(allErrs, field.Invalid(fldPath, policies, "only one routing policy can be set"))
+	}
+	if 0 < len(policies) && spec.Id == nil {
+		allErrs = append(allErrs, field.Required(fldPath.Child("id"), fmt.Sprintf("id is required for %s records", policies[0])))
+	}
+	if len(policies) == 0 && spec.Id != nil && (old == nil || 0 < len(routingPolicies(*old))) {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("id"), "id can only be set with a routing policy"))
+	}
+	if spec.Id != nil {
+		if *spec.Id == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("id"), "id must not be empty"))
+		} else if maxIdLength < len(*spec.Id) {
+			allErrs = append(allErrs, field.TooLong(fldPath.Child("id"), *spec.Id, maxIdLength))
+		}
+	}
+	if spec.GeoLocation != nil {
+		allErrs = append(allErrs, validateGeoLocation(*spec.GeoLocation, fldPath.Child("geoLocation"))...)
+	}
+	if spec.GeoProximity != nil {
+		allErrs = append(allErrs, validateGeoProximity(*spec.GeoProximity, fldPath.Child("geoProximity"))...)
+	}
+
+	if spec.HealthCheckRef != "" && healthCheckID(spec) != "" {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("healthCheckRef"), spec.HealthCheckRef, "healthCheckRef and healthCheckID are mutually exclusive"))
+	}
+
+	if spec.Rdata != "" && len(spec.Rdatas) != 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("rdatas"), spec.Rdatas, "rdata and rdatas are mutually exclusive"))
+	}
+
+	if spec.IsAlias {
+		if spec.Rdata != "" || len(spec.Rdatas) != 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("rdata"), "rdata must not be set for alias records"))
+		}
+		if spec.Ttl != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("ttl"), "ttl must not be set for alias records"))
+		}
+		if spec.AliasTarget.Record == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("aliasTarget", "record"), "alias target is required for alias records"))
+		}
+		if spec.MultiValueAnswer != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("multiValueAnswer"), "alias records can not be multivalue answer records"))
+		}
+		return allErrs
+	}
+
+	if spec.Ttl == nil {
+		allErrs = append(allErrs, field.Required(fldPath.Child("ttl"), "ttl is required"))
+	}
+	if spec.Rdata == "" && len(spec.Rdatas) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("rdata"), "rdata or an alias target is required"))
+		return allErrs
+	}
+	if (spec.Class == "CNAME" || spec.MultiValueAnswer != nil) && len(spec.Values()) > 1 {
+		allErrs = append(allErrs, field.TooMany(fldPath.Child("rdatas"), len(spec.Values()), 1))
+	}
+
+	if spec.Rdata != "" {
+		allErrs = append(allErrs, validateRdata(spec.Class, spec.Rdata, fldPath.Child("rdata"))...)
+	}
+	for i, v := range spec.Rdatas {
+		allErrs = append(allErrs, validateRdata(spec.Class, v, fldPath.Child("rdatas").Index(i))...)
+	}
+	return allErrs
+}
+
+// healthCheckID returns the health check ID set in the routing policy of spec.
+func healthCheckID(spec ResourceRecordSpec) string {
+	switch {
+	case spec.Failover != nil:
+		return spec.Failover.HealthCheckID
+	case spec.MultiValueAnswer != nil:
+		return spec.MultiValueAnswer.HealthCheckID
+	}
+	return ""
+}
+
+// routingPolicies returns the names of the routing policies set in spec.
+func routingPolicies(spec ResourceRecordSpec) []string {
+	var policies []string
+	if spec.Weight != nil {
+		policies = append(policies, "weighted")
+	}
+	if spec.Failover != nil {
+		policies = append(policies, "failover")
+	}
+	if spec.Region != "" {
+		policies = append(policies, "latency")
+	}
+	if spec.GeoLocation != nil {
+		policies = append(policies, "geolocation")
+	}
+	if spec.GeoProximity != nil {
+		policies = append(policies, "geoproximity")
+	}
+	if spec.MultiValueAnswer != nil {
+		policies = append(policies, "multivalue answer")
+	}
+	if spec.CidrRouting != nil {
+		policies = append(policies, "IP-based")
+	}
+	return policies
+}
+
+// validateGeoLocation checks that the location is either a continent or a
+// country, optionally narrowed to a subdivision.
+func validateGeoLocation(geo GeoLocation, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	switch {
+	case geo.ContinentCode == "" && geo.CountryCode == "":
+		allErrs = append(allErrs, field.Required(fldPath, "continentCode or countryCode is required"))
+	case geo.ContinentCode != "" && geo.CountryCode != "":
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("countryCode"), geo.CountryCode, "continentCode and countryCode are mutually exclusive"))
+	}
+	if geo.SubdivisionCode != "" && (geo.CountryCode == "" || geo.IsDefault()) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("subdivisionCode"), geo.SubdivisionCode, "subdivisionCode requires a countryCode"))
+	}
+	return allErrs
+}
+
+// validateGeoProximity checks that the location is either an AWS region or
+// coordinates, and that the bias is in range.
+func validateGeoProximity(gp GeoProximity, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	switch {
+	case gp.AWSRegion == "" && gp.Coordinates == nil:
+		allErrs = append(allErrs, field.Required(fldPath, "awsRegion or coordinates is required"))
+	case gp.AWSRegion != "" && gp.Coordinates != nil:
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("coordinates"), *gp.Coordinates, "awsRegion and coordinates are mutually exclusive"))
+	}
+	if c := gp.Coordinates; c != nil {
+		if lat, err := strconv.ParseFloat(c.Latitude, 64); err != nil || lat < -90 || 90 < lat {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("coordinates", "latitude"), c.Latitude, "must be a number between -90 and 90"))
+		}
+		if lon, err := strconv.ParseFloat(c.Longitude, 64); err != nil || lon < -180 || 180 < lon {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("coordinates", "longitude"), c.Longitude, "must be a number between -180 and 180"))
+		}
+	}
+	if gp.Bias != nil && (*gp.Bias < -99 || 99 < *gp.Bias) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("bias"), *gp.Bias, "must be between -99 and 99"))
+	}
+	return allErrs
+}
+
+// siblings returns the other ResourceRecords which write records of the same
+// class for the same owner, i.e. the records of the same names.
+func (r *ResourceRecord) siblings(c client.Reader) ([]ResourceRecord, error) {
+	var list ResourceRecordList
+	if err := c.List(context.TODO(), &list, client.InNamespace(r.Namespace)); err != nil {
+		return nil, err
+	}
+
+	var siblings []ResourceRecord
+	for _, item := range list.Items {
+		if item.Name == r.Name || !item.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if item.Spec.OwnerRef == r.Spec.OwnerRef && item.Spec.ProviderRef == r.Spec.ProviderRef && item.Spec.Class == r.Spec.Class {
+			siblings = append(siblings, item)
+		}
+	}
+	return siblings, nil
+}
+
+// validateSiblings checks that r can share its names with the siblings: the
+// ids must be unique, a failover pair has at most one primary and one
+// secondary, and there is one latency, geolocation or IP-based record per
+// region or location. A missing record of a failover pair or a missing
+// default geolocation is reported by the controller, as the records may be
+// created in any order.
+func validateSiblings(r *ResourceRecord, siblings []ResourceRecord, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for _, s := range siblings {
+		if r.Spec.Id != nil && s.Spec.Id != nil && *r.Spec.Id == *s.Spec.Id {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("id"), *r.Spec.Id, fmt.Sprintf("id is already used by ResourceRecord %s", s.Name)))
+		}
+		if r.Spec.Failover != nil && s.Spec.Failover != nil && r.Spec.Failover.Type == s.Spec.Failover.Type {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("failover", "type"), r.Spec.Failover.Type, fmt.Sprintf("ResourceRecord %s is already the %s record", s.Name, s.Spec.Failover.Type)))
+		}
+		if r.Spec.Region != "" && r.Spec.Region == s.Spec.Region {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("region"), r.Spec.Region, fmt.Sprintf("ResourceRecord %s is already the latency record of the region", s.Name)))
+		}
+		if r.Spec.CidrRouting != nil && s.Spec.CidrRouting != nil && *r.Spec.CidrRouting == *s.Spec.CidrRouting {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("cidrRouting"), *r.Spec.CidrRouting, fmt.Sprintf("ResourceRecord %s is already the IP-based record of the location", s.Name)))
+		}
+		if r.Spec.GeoLocation != nil && s.Spec.GeoLocation != nil && *r.Spec.GeoLocation == *s.Spec.GeoLocation {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("geoLocation"), *r.Spec.GeoLocation, fmt.Sprintf("ResourceRecord %s is already the geolocation record of the location", s.Name)))
+		}
+	}
+	return allErrs
+}
+
+// validateRdata checks a single value in the presentation format of its class.
+func validateRdata(class, rdata string, fldPath *field.Path) field.ErrorList {
+	var err error
+	switch class {
+	case "A":
+		if ip := net.ParseIP(rdata); ip == nil || ip.To4() == nil || strings.Contains(rdata, ":") {
+			err = fmt.Errorf("must be an IPv4 address")
+		}
+	case "AAAA":
+		if ip := net.ParseIP(rdata); ip == nil || !strings.Contains(rdata, ":") {
+			err = fmt.Errorf("must be an IPv6 address")
+		}
+	case "CNAME", "NS":
+		err = validateHostname(rdata)
+	case "MX":
+		err = validateMX(rdata)
+	case "SRV":
+		err = validateSRV(rdata)
+	case "TXT":
+		err = validateTXT(rdata)
+	}
+	if err != nil {
+		return field.ErrorList{field.Invalid(fldPath, rdata, err.Error())}
+	}
+	return nil
+}
+
+func validateHostname(name string) error {
+	if len(strings.TrimSuffix(name, ".")) > 253 || !hostnameRegexp.MatchString(name) {
+		return fmt.Errorf("must be a valid hostname")
+	}
+	return nil
+}
+
+// validateMX checks the "<pref> <host>" format.
+func validateMX(rdata string) error {
+	fields := strings.Fields(rdata)
+	if len(fields) != 2 {
+		return fmt.Errorf("must be in the format <pref> <host>")
+	}
+	if _, err := strconv.ParseUint(fields[0], 10, 16); err != nil {
+		return fmt.Errorf("preference must be an integer between 0 and 65535")
+	}
+	return validateHostname(fields[1])
+}
+
+// validateSRV checks the "<prio> <weight> <port> <target>" format.
+func validateSRV(rdata string) error {
+	fields := strings.Fields(rdata)
+	if len(fields) != 4 {
+		return fmt.Errorf("must be in the format <prio> <weight> <port> <target>")
+	}
+	for i, name := range []string{"priority", "weight", "port"} {
+		if _, err := strconv.ParseUint(fields[i], 10, 16); err != nil {
+			return fmt.Errorf("%s must be an integer between 0 and 65535", name)
+		}
+	}
+	return validateHostname(fields[3])
+}
+
+// validateTXT checks that the value is one or more quoted character-strings
+// within the Route53 length limits.
+func validateTXT(rdata string) error {
+	if len(rdata) > maxRdataLength {
+		return fmt.Errorf("must be no more than %d characters", maxRdataLength)
+	}
+	rest := strings.TrimSpace(rdata)
+	if rest == "" {
+		return fmt.Errorf("must be a quoted string")
+	}
+	for rest != "" {
+		if rest[0] != '"' {
+			return fmt.Errorf("must be a quoted string")
+		}
+		// find the closing quote, skipping escaped characters
+		end := -1
+		length := 0
+		for i := 1; i < len(rest); i++ {
+			if rest[i] == '\\' {
+				i++
+				length++
+				continue
+			}
+			if rest[i] == '"' {
+				end = i
+				break
+			}
+			length++
+		}
+		if end < 0 {
+			return fmt.Errorf("must be a quoted string")
+		}
+		if length > maxTXTStringLength {
+			return fmt.Errorf("each quoted string must be no more than %d characters", maxTXTStringLength)
+		}
+		rest = strings.TrimLeft(rest[end+1:], " ")
+	}
+	return nil
+}
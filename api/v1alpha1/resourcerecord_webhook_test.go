@@ -0,0 +1,605 @@
+package v1alpha1
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestValidateResourceRecord(t *testing.T) {
+	ttl := int32(300)
+	weight := int64(10)
+	id := "primary"
+	emptyId := ""
+	longId := strings.Repeat("a", maxIdLength+1)
+	bias := int32(-20)
+	tooLargeBias := int32(100)
+
+	cases := map[string]struct {
+		spec    ResourceRecordSpec
+		wantErr bool
+	}{
+		"valid A": {
+			spec: ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "192.0.2.1"},
+		},
+		"A with IPv6": {
+			spec:    ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "2001:db8::1"},
+			wantErr: true,
+		},
+		"A with IPv4-mapped IPv6": {
+			spec:    ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "::ffff:192.0.2.1"},
+			wantErr: true,
+		},
+		"valid AAAA": {
+			spec: ResourceRecordSpec{Class: "AAAA", Ttl: &ttl, Rdata: "2001:db8::1"},
+		},
+		"AAAA with IPv4": {
+			spec:    ResourceRecordSpec{Class: "AAAA", Ttl: &ttl, Rdata: "192.0.2.1"},
+			wantErr: true,
+		},
+		"invalid value in rdatas": {
+			spec:    ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdatas: []string{"192.0.2.1", "example.com"}},
+			wantErr: true,
+		},
+		"valid MX": {
+			spec: ResourceRecordSpec{Class: "MX", Ttl: &ttl, Rdata: "10 mail.example.com."},
+		},
+		"MX without preference": {
+			spec:    ResourceRecordSpec{Class: "MX", Ttl: &ttl, Rdata: "mail.example.com."},
+			wantErr: true,
+		},
+		"MX with too large preference": {
+			spec:    ResourceRecordSpec{Class: "MX", Ttl: &ttl, Rdata: "65536 mail.example.com."},
+			wantErr: true,
+		},
+		"valid SRV": {
+			spec: ResourceRecordSpec{Class: "SRV", Ttl: &ttl, Rdata: "10 5 5060 sip.example.com."},
+		},
+		"SRV with missing port": {
+			spec:    ResourceRecordSpec{Class: "SRV", Ttl: &ttl, Rdata: "10 5 sip.example.com."},
+			wantErr: true,
+		},
+		"valid CNAME": {
+			spec: ResourceRecordSpec{Class: "CNAME", Ttl: &ttl, Rdata: "www.example.com"},
+		},
+		"CNAME with invalid hostname": {
+			spec:    ResourceRecordSpec{Class: "CNAME", Ttl: &ttl, Rdata: "www example.com"},
+			wantErr: true,
+		},
+		"CNAME with several values": {
+			spec:    ResourceRecordSpec{Class: "CNAME", Ttl: &ttl, Rdatas: []string{"a.example.com", "b.example.com"}},
+			wantErr: true,
+		},
+		"valid TXT": {
+			spec: ResourceRecordSpec{Class: "TXT", Ttl: &ttl, Rdata: `"v=spf1 -all"`},
+		},
+		"TXT with several strings": {
+			spec: ResourceRecordSpec{Class: "TXT", Ttl: &ttl, Rdata: `"part one" "part \"two\""`},
+		},
+		"unquoted TXT": {
+			spec:    ResourceRecordSpec{Class: "TXT", Ttl: &ttl, Rdata: "v=spf1 -all"},
+			wantErr: true,
+		},
+		"TXT with too long string": {
+			spec:    ResourceRecordSpec{Class: "TXT", Ttl: &ttl, Rdata: `"` + strings.Repeat("a", 256) + `"`},
+			wantErr: true,
+		},
+		"missing ttl": {
+			spec:    ResourceRecordSpec{Class: "A", Rdata: "192.0.2.1"},
+			wantErr: true,
+		},
+		"missing rdata": {
+			spec:    ResourceRecordSpec{Class: "A", Ttl: &ttl},
+			wantErr: true,
+		},
+		"rdata and rdatas": {
+			spec:    ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "192.0.2.1", Rdatas: []string{"192.0.2.2"}},
+			wantErr: true,
+		},
+		"valid alias": {
+			spec: ResourceRecordSpec{Class: "A", IsAlias: true, AliasTarget: AliasTarget{Record: "example.com"}},
+		},
+		"alias with rdata": {
+			spec:    ResourceRecordSpec{Class: "A", IsAlias: true, Rdata: "192.0.2.1", AliasTarget: AliasTarget{Record: "example.com"}},
+			wantErr: true,
+		},
+		"alias with ttl": {
+			spec:    ResourceRecordSpec{Class: "A", IsAlias: true, Ttl: &ttl, AliasTarget: AliasTarget{Record: "example.com"}},
+			wantErr: true,
+		},
+		"alias without target": {
+			spec:    ResourceRecordSpec{Class: "A", IsAlias: true},
+			wantErr: true,
+		},
+		"weighted with id": {
+			spec: ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "192.0.2.1", Weight: &weight, Id: &id},
+		},
+		"weighted without id": {
+			spec:    ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "192.0.2.1", Weight: &weight},
+			wantErr: true,
+		},
+		"failover with id": {
+			spec: ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "192.0.2.1", Id: &id, Failover: &Failover{Type: FailoverPrimary, HealthCheckID: "hc-1"}},
+		},
+		"failover without id": {
+			spec:    ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "192.0.2.1", Failover: &Failover{Type: FailoverPrimary}},
+			wantErr: true,
+		},
+		"latency with id": {
+			spec: ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "192.0.2.1", Id: &id, Region: "ap-northeast-1"},
+		},
+		"latency without id": {
+			spec:    ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "192.0.2.1", Region: "ap-northeast-1"},
+			wantErr: true,
+		},
+		"geolocation country": {
+			spec: ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "192.0.2.1", Id: &id, GeoLocation: &GeoLocation{CountryCode: "DE"}},
+		},
+		"geolocation subdivision": {
+			spec: ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "192.0.2.1", Id: &id, GeoLocation: &GeoLocation{CountryCode: "US", SubdivisionCode: "CA"}},
+		},
+		"geolocation without location": {
+			spec:    ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "192.0.2.1", Id: &id, GeoLocation: &GeoLocation{}},
+			wantErr: true,
+		},
+		"geolocation continent and country": {
+			spec:    ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "192.0.2.1", Id: &id, GeoLocation: &GeoLocation{ContinentCode: "EU", CountryCode: "DE"}},
+			wantErr: true,
+		},
+		"geolocation subdivision without country": {
+			spec:    ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "192.0.2.1", Id: &id, GeoLocation: &GeoLocation{ContinentCode: "NA", SubdivisionCode: "CA"}},
+			wantErr: true,
+		},
+		"geoproximity region with bias": {
+			spec: ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "192.0.2.1", Id: &id, GeoProximity: &GeoProximity{AWSRegion: "ap-northeast-1", Bias: &bias}},
+		},
+		"geoproximity coordinates": {
+			spec: ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "192.0.2.1", Id: &id, GeoProximity: &GeoProximity{Coordinates: &Coordinates{Latitude: "35.68", Longitude: "139.76"}}},
+		},
+		"geoproximity region and coordinates": {
+			spec:    ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "192.0.2.1", Id: &id, GeoProximity: &GeoProximity{AWSRegion: "ap-northeast-1", Coordinates: &Coordinates{Latitude: "35.68", Longitude: "139.76"}}},
+			wantErr: true,
+		},
+		"geoproximity without location": {
+			spec:    ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "192.0.2.1", Id: &id, GeoProximity: &GeoProximity{Bias: &bias}},
+			wantErr: true,
+		},
+		"geoproximity out of range bias": {
+			spec:    ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "192.0.2.1", Id: &id, GeoProximity: &GeoProximity{AWSRegion: "ap-northeast-1", Bias: &tooLargeBias}},
+			wantErr: true,
+		},
+		"geoproximity out of range latitude": {
+			spec:    ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "192.0.2.1", Id: &id, GeoProximity: &GeoProximity{Coordinates: &Coordinates{Latitude: "95", Longitude: "139.76"}}},
+			wantErr: true,
+		},
+		"multivalue answer": {
+			spec: ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "192.0.2.1", Id: &id, MultiValueAnswer: &MultiValueAnswer{HealthCheckID: "hc-1"}},
+		},
+		"multivalue answer with several values": {
+			spec:    ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdatas: []string{"192.0.2.1", "192.0.2.2"}, Id: &id, MultiValueAnswer: &MultiValueAnswer{}},
+			wantErr: true,
+		},
+		"multivalue answer alias": {
+			spec:    ResourceRecordSpec{Class: "A", IsAlias: true, AliasTarget: AliasTarget{Record: "example.com."}, Id: &id, MultiValueAnswer: &MultiValueAnswer{}},
+			wantErr: true,
+		},
+		"IP-based": {
+			spec: ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "192.0.2.1", Id: &id, CidrRouting: &CidrRouting{CollectionRef: "offices", LocationName: "tokyo"}},
+		},
+		"IP-based without id": {
+			spec:    ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "192.0.2.1", CidrRouting: &CidrRouting{CollectionRef: "offices", LocationName: "tokyo"}},
+			wantErr: true,
+		},
+		"health check ref": {
+			spec: ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "192.0.2.1", Id: &id, Failover: &Failover{Type: FailoverPrimary}, HealthCheckRef: "web"},
+		},
+		"health check ref and id": {
+			spec:    ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "192.0.2.1", Id: &id, Failover: &Failover{Type: FailoverPrimary, HealthCheckID: "hc-1"}, HealthCheckRef: "web"},
+			wantErr: true,
+		},
+		"id without routing policy": {
+			spec:    ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "192.0.2.1", Id: &id},
+			wantErr: true,
+		},
+		"empty id": {
+			spec:    ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "192.0.2.1", Id: &emptyId, Weight: &weight},
+			wantErr: true,
+		},
+		"too long id": {
+			spec:    ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "192.0.2.1", Id: &longId, Weight: &weight},
+			wantErr: true,
+		},
+		"weighted failover": {
+			spec:    ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "192.0.2.1", Id: &id, Weight: &weight, Failover: &Failover{Type: FailoverPrimary}},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &ResourceRecord{Spec: tc.spec}
+			err := r.validate(nil)
+			if tc.wantErr && err == nil {
+				t.Errorf("expected an error, got nil")
+			}
+			if !tc.wantErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateResourceRecordUpdate(t *testing.T) {
+	ttl := int32(300)
+	id := "primary"
+	otherId := "secondary"
+	old := ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "192.0.2.1", ProviderRef: "provider", Id: &id, Failover: &Failover{Type: FailoverPrimary}}
+
+	cases := map[string]struct {
+		spec    ResourceRecordSpec
+		wantErr bool
+	}{
+		"change value": {
+			spec: ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "192.0.2.2", ProviderRef: "provider", Id: &id, Failover: &Failover{Type: FailoverPrimary}},
+		},
+		"change class": {
+			spec:    ResourceRecordSpec{Class: "AAAA", Ttl: &ttl, Rdata: "2001:db8::1", ProviderRef: "provider", Id: &id, Failover: &Failover{Type: FailoverPrimary}},
+			wantErr: true,
+		},
+		"change id": {
+			spec:    ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "192.0.2.1", ProviderRef: "provider", Id: &otherId, Failover: &Failover{Type: FailoverPrimary}},
+			wantErr: true,
+		},
+		"remove id": {
+			spec:    ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "192.0.2.1", ProviderRef: "provider"},
+			wantErr: true,
+		},
+		"change provider": {
+			spec:    ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "192.0.2.1", ProviderRef: "other", Id: &id, Failover: &Failover{Type: FailoverPrimary}},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			errs := validateResourceRecordSpecUpdate(tc.spec, old, field.NewPath("spec"))
+			if tc.wantErr && len(errs) == 0 {
+				t.Errorf("expected an error, got nil")
+			}
+			if !tc.wantErr && len(errs) != 0 {
+				t.Errorf("unexpected error: %v", errs)
+			}
+		})
+	}
+}
+
+func TestDefaultResourceRecord(t *testing.T) {
+	ttl := int32(60)
+
+	cases := map[string]struct {
+		spec ResourceRecordSpec
+		want ResourceRecordSpec
+	}{
+		"default ttl": {
+			spec: ResourceRecordSpec{Class: "A", Rdata: "192.0.2.1"},
+			want: ResourceRecordSpec{Class: "A", Ttl: func() *int32 { v := DefaultTtl; return &v }(), Rdata: "192.0.2.1"},
+		},
+		"keep ttl": {
+			spec: ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "192.0.2.1"},
+			want: ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "192.0.2.1"},
+		},
+		"fqdn CNAME": {
+			spec: ResourceRecordSpec{Class: "CNAME", Ttl: &ttl, Rdata: "www.example.com"},
+			want: ResourceRecordSpec{Class: "CNAME", Ttl: &ttl, Rdata: "www.example.com."},
+		},
+		"fqdn MX": {
+			spec: ResourceRecordSpec{Class: "MX", Ttl: &ttl, Rdatas: []string{"10 mx1.example.com", "20  mx2.example.com."}},
+			want: ResourceRecordSpec{Class: "MX", Ttl: &ttl, Rdatas: []string{"10 mx1.example.com.", "20 mx2.example.com."}},
+		},
+		"fqdn SRV": {
+			spec: ResourceRecordSpec{Class: "SRV", Ttl: &ttl, Rdata: "10 5 5060 sip.example.com"},
+			want: ResourceRecordSpec{Class: "SRV", Ttl: &ttl, Rdata: "10 5 5060 sip.example.com."},
+		},
+		"quote TXT": {
+			spec: ResourceRecordSpec{Class: "TXT", Ttl: &ttl, Rdata: `say "hello"`},
+			want: ResourceRecordSpec{Class: "TXT", Ttl: &ttl, Rdata: `"say \"hello\""`},
+		},
+		"keep quoted TXT": {
+			spec: ResourceRecordSpec{Class: "TXT", Ttl: &ttl, Rdata: `"v=spf1 -all"`},
+			want: ResourceRecordSpec{Class: "TXT", Ttl: &ttl, Rdata: `"v=spf1 -all"`},
+		},
+		"split long TXT": {
+			spec: ResourceRecordSpec{Class: "TXT", Ttl: &ttl, Rdata: strings.Repeat("a", 300)},
+			want: ResourceRecordSpec{Class: "TXT", Ttl: &ttl, Rdata: `"` + strings.Repeat("a", 255) + `" "` + strings.Repeat("a", 45) + `"`},
+		},
+		"alias to CloudFront": {
+			spec: ResourceRecordSpec{Class: "A", IsAlias: true, AliasTarget: AliasTarget{Record: "d111111abcdef8.cloudfront.net"}},
+			want: ResourceRecordSpec{Class: "A", IsAlias: true, AliasTarget: AliasTarget{Record: "d111111abcdef8.cloudfront.net.", HostedZoneID: "Z2FDTNDATAQYW2"}},
+		},
+		"alias drops ttl": {
+			spec: ResourceRecordSpec{Class: "A", Ttl: &ttl, IsAlias: true, AliasTarget: AliasTarget{Record: "example.com.", HostedZoneID: "Z0000000000000"}},
+			want: ResourceRecordSpec{Class: "A", IsAlias: true, AliasTarget: AliasTarget{Record: "example.com.", HostedZoneID: "Z0000000000000"}},
+		},
+		"alias keeps hosted zone id": {
+			spec: ResourceRecordSpec{Class: "A", IsAlias: true, AliasTarget: AliasTarget{Record: "example.com.", HostedZoneID: "Z0000000000000"}},
+			want: ResourceRecordSpec{Class: "A", IsAlias: true, AliasTarget: AliasTarget{Record: "example.com.", HostedZoneID: "Z0000000000000"}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &ResourceRecord{Spec: tc.spec}
+			r.Default()
+			if !reflect.DeepEqual(r.Spec, tc.want) {
+				t.Errorf("expected %+v, got %+v", tc.want, r.Spec)
+			}
+			if err := r.validate(nil); err != nil {
+				t.Errorf("defaulted record is invalid: %v", err)
+			}
+		})
+	}
+}
+
+// TestValidateUpdateOfOldRecords updates records in the shape accepted by
+// older versions, which required a ttl on alias records and accepted an id
+// without a routing policy.
+func TestValidateUpdateOfOldRecords(t *testing.T) {
+	ttl := int32(300)
+	weight := int64(10)
+	id := "blue"
+
+	cases := map[string]struct {
+		old     ResourceRecordSpec
+		update  func(r *ResourceRecord)
+		wantErr bool
+	}{
+		"add the finalizer to an alias with ttl": {
+			old:    ResourceRecordSpec{Class: "A", Ttl: &ttl, IsAlias: true, AliasTarget: AliasTarget{Record: "example.com.", HostedZoneID: "Z0000000000000"}},
+			update: func(r *ResourceRecord) { r.Finalizers = []string{"dns.ch1aki.github.io/finalizer"} },
+		},
+		"change the target of an alias with ttl": {
+			old:    ResourceRecordSpec{Class: "A", Ttl: &ttl, IsAlias: true, AliasTarget: AliasTarget{Record: "example.com.", HostedZoneID: "Z0000000000000"}},
+			update: func(r *ResourceRecord) { r.Spec.AliasTarget.Record = "www.example.com." },
+		},
+		"add the finalizer to a record with id and without weight": {
+			old:    ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "192.0.2.1", Id: &id},
+			update: func(r *ResourceRecord) { r.Finalizers = []string{"dns.ch1aki.github.io/finalizer"} },
+		},
+		"change the value of a record with id and without weight": {
+			old:    ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "192.0.2.1", Id: &id},
+			update: func(r *ResourceRecord) { r.Spec.Rdata = "192.0.2.2" },
+		},
+		"remove the weight and keep the id": {
+			old:     ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "192.0.2.1", Id: &id, Weight: &weight},
+			update:  func(r *ResourceRecord) { r.Spec.Weight = nil },
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			useClient(t)
+			old := &ResourceRecord{Spec: tc.old}
+			old.Name = "record"
+			r := old.DeepCopy()
+			tc.update(r)
+			r.Default()
+			err := r.ValidateUpdate(old)
+			if tc.wantErr && err == nil {
+				t.Errorf("expected an error, got nil")
+			}
+			if !tc.wantErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestCanonicalHostedZoneID(t *testing.T) {
+	cases := map[string]string{
+		"d111111abcdef8.cloudfront.net.":                           "Z2FDTNDATAQYW2",
+		"my-alb-1234567890.ap-northeast-1.elb.amazonaws.com":       "Z14GRHDCWA56QT",
+		"dualstack.my-alb-1234567890.us-east-1.elb.amazonaws.com.": "Z35SXDOTRQ7X7K",
+		"my-nlb-0123456789abcdef.elb.ap-northeast-1.amazonaws.com": "Z31USIVHYNEOWT",
+		"my-bucket.s3-website-us-west-2.amazonaws.com":             "Z3BJ6K6RIION7M",
+		"my-bucket.s3-website.eu-central-1.amazonaws.com":          "Z21DNDUVLTQW6Q",
+		"my-alb-1234567890.unknown-region-1.elb.amazonaws.com":     "",
+		"www.example.com": "",
+	}
+	for hostname, want := range cases {
+		t.Run(hostname, func(t *testing.T) {
+			if got := canonicalHostedZoneID(hostname); got != want {
+				t.Errorf("expected %q, got %q", want, got)
+			}
+		})
+	}
+}
+
+func TestValidateSiblings(t *testing.T) {
+	newRecord := func(name, id string, failover FailoverType) ResourceRecord {
+		r := ResourceRecord{Spec: ResourceRecordSpec{Class: "A", OwnerRef: "owner", ProviderRef: "provider", Id: &id}}
+		r.Name = name
+		if failover != "" {
+			r.Spec.Failover = &Failover{Type: failover}
+		}
+		return r
+	}
+	withRegion := func(r ResourceRecord, region string) ResourceRecord {
+		r.Spec.Region = region
+		return r
+	}
+	withCidr := func(r ResourceRecord, location string) ResourceRecord {
+		r.Spec.CidrRouting = &CidrRouting{CollectionRef: "offices", LocationName: location}
+		return r
+	}
+	withGeo := func(r ResourceRecord, geo GeoLocation) ResourceRecord {
+		r.Spec.GeoLocation = &geo
+		return r
+	}
+
+	cases := map[string]struct {
+		record   ResourceRecord
+		siblings []ResourceRecord
+		wantErr  bool
+	}{
+		"primary and secondary": {
+			record:   newRecord("secondary", "secondary", FailoverSecondary),
+			siblings: []ResourceRecord{newRecord("primary", "primary", FailoverPrimary)},
+		},
+		"primary of secondary": {
+			record:   newRecord("primary", "primary", FailoverPrimary),
+			siblings: []ResourceRecord{newRecord("secondary", "secondary", FailoverSecondary)},
+		},
+		"lone primary": {
+			record: newRecord("primary", "primary", FailoverPrimary),
+		},
+		"second primary": {
+			record:   newRecord("primary-2", "primary-2", FailoverPrimary),
+			siblings: []ResourceRecord{newRecord("primary", "primary", FailoverPrimary), newRecord("secondary", "secondary", FailoverSecondary)},
+			wantErr:  true,
+		},
+		"latency records in different regions": {
+			record:   withRegion(newRecord("tokyo", "tokyo", ""), "ap-northeast-1"),
+			siblings: []ResourceRecord{withRegion(newRecord("virginia", "virginia", ""), "us-east-1")},
+		},
+		"latency records in the same region": {
+			record:   withRegion(newRecord("tokyo-2", "tokyo-2", ""), "ap-northeast-1"),
+			siblings: []ResourceRecord{withRegion(newRecord("tokyo", "tokyo", ""), "ap-northeast-1")},
+			wantErr:  true,
+		},
+		"geolocation with default": {
+			record:   withGeo(newRecord("eu", "eu", ""), GeoLocation{ContinentCode: "EU"}),
+			siblings: []ResourceRecord{withGeo(newRecord("default", "default", ""), GeoLocation{CountryCode: "*"})},
+		},
+		"first default geolocation": {
+			record: withGeo(newRecord("default", "default", ""), GeoLocation{CountryCode: "*"}),
+		},
+		"geolocation before default": {
+			record:   withGeo(newRecord("eu", "eu", ""), GeoLocation{ContinentCode: "EU"}),
+			siblings: []ResourceRecord{withGeo(newRecord("us", "us", ""), GeoLocation{CountryCode: "US"})},
+		},
+		"geolocation with the same location": {
+			record: withGeo(newRecord("eu-2", "eu-2", ""), GeoLocation{ContinentCode: "EU"}),
+			siblings: []ResourceRecord{
+				withGeo(newRecord("default", "default", ""), GeoLocation{CountryCode: "*"}),
+				withGeo(newRecord("eu", "eu", ""), GeoLocation{ContinentCode: "EU"}),
+			},
+			wantErr: true,
+		},
+		"IP-based records of the same location": {
+			record:   withCidr(newRecord("office-2", "office-2", ""), "tokyo"),
+			siblings: []ResourceRecord{withCidr(newRecord("office", "office", ""), "tokyo")},
+			wantErr:  true,
+		},
+		"duplicate id": {
+			record:   newRecord("blue-2", "blue", ""),
+			siblings: []ResourceRecord{newRecord("blue", "blue", "")},
+			wantErr:  true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			errs := validateSiblings(&tc.record, tc.siblings, field.NewPath("spec"))
+			if tc.wantErr && len(errs) == 0 {
+				t.Errorf("expected an error, got nil")
+			}
+			if !tc.wantErr && len(errs) != 0 {
+				t.Errorf("unexpected error: %v", errs)
+			}
+		})
+	}
+}
+
+// useClient makes the webhook look the siblings up in objs during the test.
+func useClient(t *testing.T, objs ...client.Object) {
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	resourcerecordClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	t.Cleanup(func() { resourcerecordClient = nil })
+}
+
+func TestValidateUpdateOfFailoverPair(t *testing.T) {
+	ttl := int32(300)
+	newRecord := func(name string, failover FailoverType, deleting bool) *ResourceRecord {
+		r := &ResourceRecord{Spec: ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "192.0.2.1", OwnerRef: "owner", ProviderRef: "provider", Id: &name, Failover: &Failover{Type: failover}}}
+		r.Name = name
+		r.Namespace = "default"
+		r.Finalizers = []string{"dns.ch1aki.github.io/finalizer"}
+		if deleting {
+			now := metav1.Now()
+			r.DeletionTimestamp = &now
+		}
+		return r
+	}
+
+	t.Run("remove the finalizers of a deleted pair", func(t *testing.T) {
+		primary, secondary := newRecord("primary", FailoverPrimary, true), newRecord("secondary", FailoverSecondary, true)
+		useClient(t, primary, secondary)
+		for _, old := range []*ResourceRecord{primary, secondary} {
+			r := old.DeepCopy()
+			r.Finalizers = nil
+			if err := r.ValidateUpdate(old); err != nil {
+				t.Errorf("unexpected error for %s: %v", old.Name, err)
+			}
+		}
+	})
+
+	t.Run("create the primary before the secondary", func(t *testing.T) {
+		useClient(t)
+		if err := newRecord("primary", FailoverPrimary, false).ValidateCreate(); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("change the secondary into a second primary", func(t *testing.T) {
+		primary, secondary := newRecord("primary", FailoverPrimary, false), newRecord("secondary", FailoverSecondary, false)
+		useClient(t, primary, secondary)
+		r := secondary.DeepCopy()
+		r.Spec.Failover.Type = FailoverPrimary
+		if err := r.ValidateUpdate(secondary); err == nil {
+			t.Errorf("expected an error, got nil")
+		}
+	})
+}
+
+func TestValidateUpdateOfGeoLocationSet(t *testing.T) {
+	ttl := int32(300)
+	newRecord := func(name string, geo GeoLocation) *ResourceRecord {
+		r := &ResourceRecord{Spec: ResourceRecordSpec{Class: "A", Ttl: &ttl, Rdata: "192.0.2.1", OwnerRef: "owner", ProviderRef: "provider", Id: &name, GeoLocation: &geo}}
+		r.Name = name
+		r.Namespace = "default"
+		r.Finalizers = []string{"dns.ch1aki.github.io/finalizer"}
+		now := metav1.Now()
+		r.DeletionTimestamp = &now
+		return r
+	}
+
+	set := []*ResourceRecord{
+		newRecord("default", GeoLocation{CountryCode: "*"}),
+		newRecord("eu", GeoLocation{ContinentCode: "EU"}),
+		newRecord("us", GeoLocation{CountryCode: "US"}),
+	}
+	objs := make([]client.Object, len(set))
+	for i, r := range set {
+		objs[i] = r
+	}
+	useClient(t, objs...)
+
+	// the default location goes first, the others must still be released
+	for _, old := range set {
+		r := old.DeepCopy()
+		r.Finalizers = nil
+		if err := r.ValidateUpdate(old); err != nil {
+			t.Errorf("unexpected error for %s: %v", old.Name, err)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	dnsv1alpha1 "github.com/ch1aki/dns-rr/api/v1alpha1"
+	"github.com/ch1aki/dns-rr/controllers/provider"
+)
+
+// cidrCollectionInUsePollInterval is how often the deletion of a collection
+// checks whether ResourceRecords still route by it.
+const cidrCollectionInUsePollInterval = 30 * time.Second
+
+// CidrCollectionReconciler reconciles a CidrCollection object
+type CidrCollectionReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=dns.ch1aki.github.io,resources=cidrcollections,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=dns.ch1aki.github.io,resources=cidrcollections/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=dns.ch1aki.github.io,resources=cidrcollections/finalizers,verbs=update
+//+kubebuilder:rbac:groups=dns.ch1aki.github.io,resources=resourcerecords,verbs=get;list;watch
+
+// Reconcile creates the CIDR collection in the provider and makes its
+// locations equal to the spec. The collection is deleted with the object.
+func (r *CidrCollectionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var c dnsv1alpha1.CidrCollection
+	err := r.Get(ctx, req.NamespacedName, &c)
+	if apierrors.IsNotFound(err) {
+		return ctrl.Result{}, nil
+	}
+	if err != nil {
+		logger.Error(err, "unable to get CidrCollection", "name", req.NamespacedName)
+		return ctrl.Result{}, err
+	}
+
+	if !c.ObjectMeta.DeletionTimestamp.IsZero() {
+		return r.reconcileDelete(ctx, &c)
+	}
+
+	if err := addFinalizer(ctx, r, &c); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	cidrProvider, err := r.cidrProvider(ctx, &c)
+	if apierrors.IsNotFound(err) {
+		return ctrl.Result{}, r.setNotReady(ctx, &c, "ProviderNotFound", fmt.Sprintf("provider %s not found", c.Spec.ProviderRef))
+	}
+	if err != nil {
+		logger.Error(err, "failed initialize client")
+		return syncFailed(err, r.setNotReady(ctx, &c, "ClientError", err.Error()))
+	}
+
+	locations := make(map[string][]string, len(c.Spec.Locations))
+	for _, l := range c.Spec.Locations {
+		locations[l.Name] = l.CidrBlocks
+	}
+	// the UID is the caller reference which marks the collection as owned
+	// by this object, since collections are shared by the whole account
+	collection, err := provider.ConvergeCidrCollection(ctx, cidrProvider, c.CollectionName(), string(c.UID), locations)
+	if errors.Is(err, provider.ErrCidrCollectionNotOwned) {
+		return syncFailed(err, r.setNotReady(ctx, &c, "CollectionNotOwned", err.Error()))
+	}
+	if err != nil {
+		logger.Error(err, "failed converge cidr collection")
+		return syncFailed(err, r.setNotReady(ctx, &c, "ConvergeFailed", err.Error()))
+	}
+
+	c.Status.CollectionID = collection.ID
+	c.Status.Version = collection.Version
+	setCidrCollectionCondition(&c, metav1.ConditionTrue, "Synced", "collection is in sync with provider")
+	if err := r.Status().Update(ctx, &c); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (r *CidrCollectionReconciler) reconcileDelete(ctx context.Context, c *dnsv1alpha1.CidrCollection) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(c, finalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	// the collection can not be deleted while records route by it
+	var records dnsv1alpha1.ResourceRecordList
+	if err := r.List(ctx, &records, client.InNamespace(c.Namespace), client.MatchingFields{cidrCollectionField: c.Name}); err != nil {
+		return ctrl.Result{}, err
+	}
+	if 0 < len(records.Items) {
+		if err := r.setNotReady(ctx, c, "CollectionInUse", fmt.Sprintf("collection is used by %d ResourceRecords, e.g. %s", len(records.Items), records.Items[0].Name)); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: cidrCollectionInUsePollInterval}, nil
+	}
+
+	cidrProvider, err := r.cidrProvider(ctx, c)
+	if apierrors.IsNotFound(err) {
+		// the provider is kept while collections reference it, so it was removed by force
+		err = &provider.TerminalError{Err: fmt.Errorf("provider %s not found, can not delete cidr collection %s", c.Spec.ProviderRef, c.CollectionName())}
+	} else if err == nil {
+		err = provider.DeleteCidrCollection(ctx, cidrProvider, c.CollectionName(), string(c.UID))
+	}
+	if errors.Is(err, provider.ErrCidrCollectionNotOwned) {
+		logger.Info("cidr collection is owned by another object, skip deleting it", "collectionName", c.CollectionName())
+		err = nil
+	}
+	if err != nil {
+		logger.Error(err, "failed delete cidr collection")
+		if serr := r.setNotReady(ctx, c, "DeleteFailed", err.Error()); serr != nil {
+			logger.Error(serr, "unable to update CidrCollection status")
+		}
+		return ctrl.Result{}, err
+	}
+
+	return removeFinalizer(ctx, r, c)
+}
+
+// cidrProvider builds the backend of the Provider referenced by the collection.
+func (r *CidrCollectionReconciler) cidrProvider(ctx context.Context, c *dnsv1alpha1.CidrCollection) (provider.CidrCollectionProvider, error) {
+	var p dnsv1alpha1.Provider
+	if err := r.Get(ctx, client.ObjectKey{Namespace: c.Namespace, Name: c.Spec.ProviderRef}, &p); err != nil {
+		return nil, err
+	}
+	dnsProvider, err := provider.New(ctx, &p, r.Client)
+	if err != nil {
+		return nil, err
+	}
+	return provider.AsCidrCollectionProvider(dnsProvider)
+}
+
+// setNotReady records the reason why the collection could not be synced.
+func (r *CidrCollectionReconciler) setNotReady(ctx context.Context, c *dnsv1alpha1.CidrCollection, reason, message string) error {
+	setCidrCollectionCondition(c, metav1.ConditionFalse, reason, message)
+	return r.Status().Update(ctx, c)
+}
+
+func setCidrCollectionCondition(c *dnsv1alpha1.CidrCollection, status metav1.ConditionStatus, reason, message string) {
+	setCondition(&c.Status.Conditions, &c.Status.ObservedGeneration, c.Generation, dnsv1alpha1.CidrCollectionConditionReady, status, reason, message)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CidrCollectionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &dnsv1alpha1.CidrCollection{}, providerField, func(rawObj client.Object) []string {
+		return []string{rawObj.(*dnsv1alpha1.CidrCollection).Spec.ProviderRef}
+	}); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&dnsv1alpha1.CidrCollection{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
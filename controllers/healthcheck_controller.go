@@ -0,0 +1,304 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	dnsv1alpha1 "github.com/ch1aki/dns-rr/api/v1alpha1"
+	"github.com/ch1aki/dns-rr/controllers/provider"
+)
+
+const childHealthChecksField = ".spec.childHealthChecks"
+
+// DefaultHealthStatusInterval is how often the status of a health check is
+// refreshed by default.
+const DefaultHealthStatusInterval = 5 * time.Minute
+
+// HealthCheckReconciler reconciles a HealthCheck object
+type HealthCheckReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// HealthStatusInterval is how often the status of a health check is
+	// refreshed. It defaults to DefaultHealthStatusInterval.
+	HealthStatusInterval time.Duration
+}
+
+//+kubebuilder:rbac:groups=dns.ch1aki.github.io,resources=healthchecks,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=dns.ch1aki.github.io,resources=healthchecks/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=dns.ch1aki.github.io,resources=healthchecks/finalizers,verbs=update
+
+// Reconcile creates or updates the health check in the provider when its spec
+// changed and refreshes its status periodically. The status is only written
+// when it changed. The health check is deleted with the object.
+func (r *HealthCheckReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var hc dnsv1alpha1.HealthCheck
+	err := r.Get(ctx, req.NamespacedName, &hc)
+	if apierrors.IsNotFound(err) {
+		return ctrl.Result{}, nil
+	}
+	if err != nil {
+		logger.Error(err, "unable to get HealthCheck", "name", req.NamespacedName)
+		return ctrl.Result{}, err
+	}
+
+	if !hc.ObjectMeta.DeletionTimestamp.IsZero() {
+		return r.reconcileDelete(ctx, &hc)
+	}
+
+	if err := addFinalizer(ctx, r, &hc); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// resolve the children of a calculated health check
+	children := make([]dnsv1alpha1.HealthCheck, len(hc.Spec.ChildHealthChecks))
+	childIds := make([]string, len(hc.Spec.ChildHealthChecks))
+	for i, name := range hc.Spec.ChildHealthChecks {
+		err := r.Get(ctx, client.ObjectKey{Namespace: hc.Namespace, Name: name}, &children[i])
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, r.setNotReady(ctx, &hc, "ChildHealthCheckNotFound", fmt.Sprintf("health check %s not found", name))
+		}
+		if err != nil {
+			logger.Error(err, "unable to get HealthCheck", "name", hc.Namespace+"/"+name)
+			return ctrl.Result{}, err
+		}
+		if children[i].Status.HealthCheckID == "" {
+			return ctrl.Result{}, r.setNotReady(ctx, &hc, "ChildHealthCheckNotReady", fmt.Sprintf("health check %s is not created yet", name))
+		}
+		childIds[i] = children[i].Status.HealthCheckID
+	}
+
+	hcProvider, err := r.healthCheckProvider(ctx, &hc)
+	if apierrors.IsNotFound(err) {
+		return ctrl.Result{}, r.setNotReady(ctx, &hc, "ProviderNotFound", fmt.Sprintf("provider %s not found", hc.Spec.ProviderRef))
+	}
+	if err != nil {
+		logger.Error(err, "failed initialize client")
+		return syncFailed(err, r.setNotReady(ctx, &hc, "ClientError", err.Error()))
+	}
+
+	status := hc.Status.DeepCopy()
+	if needsConverge(&hc) {
+		// the health check is found by the UID in its caller reference when
+		// the status update after its creation was lost
+		current, err := provider.ConvergeHealthCheck(ctx, hcProvider, hc.Status.HealthCheckID, string(hc.UID), hc.Spec, childIds)
+		if err != nil {
+			logger.Error(err, "failed converge health check")
+			return syncFailed(err, r.setNotReady(ctx, &hc, "ConvergeFailed", err.Error()))
+		}
+		if hc.Status.HealthCheckID != current.ID {
+			// record a new health check before the calls which may fail below
+			hc.Status.HealthCheckID = current.ID
+			hc.Status.HealthCheckVersion = current.Version
+			if err := r.Status().Update(ctx, &hc); err != nil {
+				return ctrl.Result{}, err
+			}
+			status = hc.Status.DeepCopy()
+		}
+		hc.Status.HealthCheckVersion = current.Version
+		setHealthCheckCondition(&hc, metav1.ConditionTrue, "Synced", "health check is in sync with provider")
+	}
+
+	// refresh the latest status
+	health := dnsv1alpha1.HealthStatusUnknown
+	if hc.Spec.Type == dnsv1alpha1.HealthCheckCalculated {
+		health = calculatedHealth(hc.Spec, children)
+	} else if h, err := hcProvider.HealthStatus(ctx, hc.Status.HealthCheckID); err != nil {
+		logger.Error(err, "failed get health check status", "healthCheckID", hc.Status.HealthCheckID)
+	} else {
+		health = h
+	}
+	if hc.Status.Health != health || hc.Status.LastCheckedTime == nil {
+		now := metav1.Now()
+		hc.Status.Health = health
+		hc.Status.LastCheckedTime = &now
+	}
+
+	if !equality.Semantic.DeepEqual(status, &hc.Status) {
+		if err := r.Status().Update(ctx, &hc); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// calculated health checks are refreshed by the changes of their children
+	if hc.Spec.Type == dnsv1alpha1.HealthCheckCalculated {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{RequeueAfter: r.healthStatusInterval()}, nil
+}
+
+// needsConverge reports whether the health check has to be compared with the
+// provider, as it was not synced at its generation. Calculated health checks
+// are compared on every change of their children, whose IDs may change.
+func needsConverge(hc *dnsv1alpha1.HealthCheck) bool {
+	return hc.Status.HealthCheckID == "" ||
+		hc.Status.ObservedGeneration != hc.Generation ||
+		!meta.IsStatusConditionTrue(hc.Status.Conditions, dnsv1alpha1.HealthCheckConditionReady) ||
+		hc.Spec.Type == dnsv1alpha1.HealthCheckCalculated
+}
+
+func (r *HealthCheckReconciler) healthStatusInterval() time.Duration {
+	if r.HealthStatusInterval == 0 {
+		return DefaultHealthStatusInterval
+	}
+	return r.HealthStatusInterval
+}
+
+// calculatedHealth decides the status of a calculated health check from the
+// status of its children, as the provider does not report it.
+func calculatedHealth(spec dnsv1alpha1.HealthCheckSpec, children []dnsv1alpha1.HealthCheck) dnsv1alpha1.HealthStatus {
+	if spec.Disabled {
+		return dnsv1alpha1.HealthStatusHealthy
+	}
+	var healthy int
+	for _, child := range children {
+		switch child.Status.Health {
+		case dnsv1alpha1.HealthStatusHealthy:
+			healthy++
+		case dnsv1alpha1.HealthStatusUnhealthy:
+		default:
+			return dnsv1alpha1.HealthStatusUnknown
+		}
+	}
+	threshold := len(children)
+	if spec.HealthThreshold != nil {
+		threshold = int(*spec.HealthThreshold)
+	}
+	if (threshold <= healthy) != spec.Inverted {
+		return dnsv1alpha1.HealthStatusHealthy
+	}
+	return dnsv1alpha1.HealthStatusUnhealthy
+}
+
+func (r *HealthCheckReconciler) reconcileDelete(ctx context.Context, hc *dnsv1alpha1.HealthCheck) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(hc, finalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	if hc.Status.HealthCheckID != "" {
+		hcProvider, err := r.healthCheckProvider(ctx, hc)
+		if apierrors.IsNotFound(err) {
+			// the provider is kept while health checks reference it, so it was removed by force
+			err = &provider.TerminalError{Err: fmt.Errorf("provider %s not found, can not delete health check %s", hc.Spec.ProviderRef, hc.Status.HealthCheckID)}
+		} else if err == nil {
+			// fails while records still refer to the health check
+			err = hcProvider.DeleteHealthCheck(ctx, hc.Status.HealthCheckID)
+		}
+		if err != nil {
+			logger.Error(err, "failed delete health check")
+			if serr := r.setNotReady(ctx, hc, "DeleteFailed", err.Error()); serr != nil {
+				logger.Error(serr, "unable to update HealthCheck status")
+			}
+			return ctrl.Result{}, err
+		}
+	}
+
+	return removeFinalizer(ctx, r, hc)
+}
+
+// healthCheckProvider builds the backend of the Provider referenced by the health check.
+func (r *HealthCheckReconciler) healthCheckProvider(ctx context.Context, hc *dnsv1alpha1.HealthCheck) (provider.HealthCheckProvider, error) {
+	var p dnsv1alpha1.Provider
+	if err := r.Get(ctx, client.ObjectKey{Namespace: hc.Namespace, Name: hc.Spec.ProviderRef}, &p); err != nil {
+		return nil, err
+	}
+	dnsProvider, err := provider.New(ctx, &p, r.Client)
+	if err != nil {
+		return nil, err
+	}
+	return provider.AsHealthCheckProvider(dnsProvider)
+}
+
+// setNotReady records the reason why the health check could not be synced.
+func (r *HealthCheckReconciler) setNotReady(ctx context.Context, hc *dnsv1alpha1.HealthCheck, reason, message string) error {
+	setHealthCheckCondition(hc, metav1.ConditionFalse, reason, message)
+	return r.Status().Update(ctx, hc)
+}
+
+func setHealthCheckCondition(hc *dnsv1alpha1.HealthCheck, status metav1.ConditionStatus, reason, message string) {
+	setCondition(&hc.Status.Conditions, &hc.Status.ObservedGeneration, hc.Generation, dnsv1alpha1.HealthCheckConditionReady, status, reason, message)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *HealthCheckReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &dnsv1alpha1.HealthCheck{}, childHealthChecksField, func(rawObj client.Object) []string {
+		return rawObj.(*dnsv1alpha1.HealthCheck).Spec.ChildHealthChecks
+	}); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &dnsv1alpha1.HealthCheck{}, providerField, func(rawObj client.Object) []string {
+		return []string{rawObj.(*dnsv1alpha1.HealthCheck).Spec.ProviderRef}
+	}); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&dnsv1alpha1.HealthCheck{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(
+			&source.Kind{Type: &dnsv1alpha1.HealthCheck{}},
+			handler.EnqueueRequestsFromMapFunc(r.findParents),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
+		Complete(r)
+}
+
+// findParents returns the requests of the calculated health checks which combine child.
+func (r *HealthCheckReconciler) findParents(child client.Object) []reconcile.Request {
+	parents := &dnsv1alpha1.HealthCheckList{}
+	listOps := &client.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector(childHealthChecksField, child.GetName()),
+		Namespace:     child.GetNamespace(),
+	}
+	if err := r.List(context.TODO(), parents, listOps); err != nil {
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, len(parents.Items))
+	for i, item := range parents.Items {
+		requests[i] = reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      item.GetName(),
+				Namespace: item.GetNamespace(),
+			},
+		}
+	}
+	return requests
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dnsv1alpha1 "github.com/ch1aki/dns-rr/api/v1alpha1"
+)
+
+func TestNeedsConverge(t *testing.T) {
+	ready := []metav1.Condition{{Type: dnsv1alpha1.HealthCheckConditionReady, Status: metav1.ConditionTrue, Reason: "Synced"}}
+	notReady := []metav1.Condition{{Type: dnsv1alpha1.HealthCheckConditionReady, Status: metav1.ConditionFalse, Reason: "ConvergeFailed"}}
+
+	tests := []struct {
+		name   string
+		hcType dnsv1alpha1.HealthCheckType
+		status dnsv1alpha1.HealthCheckStatus
+		want   bool
+	}{
+		{
+			name:   "not created",
+			hcType: dnsv1alpha1.HealthCheckHTTP,
+			want:   true,
+		},
+		{
+			name:   "synced at the generation",
+			hcType: dnsv1alpha1.HealthCheckHTTP,
+			status: dnsv1alpha1.HealthCheckStatus{HealthCheckID: "abc", ObservedGeneration: 2, Conditions: ready},
+			want:   false,
+		},
+		{
+			name:   "spec changed",
+			hcType: dnsv1alpha1.HealthCheckHTTP,
+			status: dnsv1alpha1.HealthCheckStatus{HealthCheckID: "abc", ObservedGeneration: 1, Conditions: ready},
+			want:   true,
+		},
+		{
+			name:   "last sync failed",
+			hcType: dnsv1alpha1.HealthCheckHTTP,
+			status: dnsv1alpha1.HealthCheckStatus{HealthCheckID: "abc", ObservedGeneration: 2, Conditions: notReady},
+			want:   true,
+		},
+		{
+			name:   "calculated",
+			hcType: dnsv1alpha1.HealthCheckCalculated,
+			status: dnsv1alpha1.HealthCheckStatus{HealthCheckID: "abc", ObservedGeneration: 2, Conditions: ready},
+			want:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hc := &dnsv1alpha1.HealthCheck{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Spec:       dnsv1alpha1.HealthCheckSpec{Type: tt.hcType},
+				Status:     tt.status,
+			}
+			if got := needsConverge(hc); got != tt.want {
+				t.Errorf("needsConverge() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,290 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	dnsv1alpha1 "github.com/ch1aki/dns-rr/api/v1alpha1"
+	"github.com/ch1aki/dns-rr/controllers/provider"
+)
+
+const delegationProviderField = ".status.delegationProviderRef"
+
+// HostedZoneReconciler reconciles a HostedZone object
+type HostedZoneReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// OwnerID identifies this controller instance in the owner TXT records
+	// of the delegations
+	OwnerID string
+}
+
+//+kubebuilder:rbac:groups=dns.ch1aki.github.io,resources=hostedzones,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=dns.ch1aki.github.io,resources=hostedzones/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=dns.ch1aki.github.io,resources=hostedzones/finalizers,verbs=update
+
+// Reconcile creates the hosted zone in the provider, keeps its comment and
+// tags in sync and writes its NS records into the parent zone. The zone is
+// deleted with the object unless it is retained.
+func (r *HostedZoneReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var hz dnsv1alpha1.HostedZone
+	err := r.Get(ctx, req.NamespacedName, &hz)
+	if apierrors.IsNotFound(err) {
+		return ctrl.Result{}, nil
+	}
+	if err != nil {
+		logger.Error(err, "unable to get HostedZone", "name", req.NamespacedName)
+		return ctrl.Result{}, err
+	}
+
+	if !hz.ObjectMeta.DeletionTimestamp.IsZero() {
+		return r.reconcileDelete(ctx, &hz)
+	}
+
+	if err := addFinalizer(ctx, r, &hz); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	dnsProvider, err := r.newProvider(ctx, hz.Namespace, hz.Spec.ProviderRef)
+	if apierrors.IsNotFound(err) {
+		return ctrl.Result{}, r.setNotReady(ctx, &hz, "ProviderNotFound", fmt.Sprintf("provider %s not found", hz.Spec.ProviderRef))
+	}
+	if err != nil {
+		logger.Error(err, "failed initialize client")
+		return syncFailed(err, r.setNotReady(ctx, &hz, "ClientError", err.Error()))
+	}
+	hzProvider, err := provider.AsHostedZoneProvider(dnsProvider)
+	if err != nil {
+		return syncFailed(err, r.setNotReady(ctx, &hz, "ClientError", err.Error()))
+	}
+
+	// the zone is found by the UID in its caller reference when the status
+	// update after its creation was lost
+	zone, err := provider.ConvergeHostedZone(ctx, hzProvider, hz.Status.HostedZoneID, string(hz.UID), hz.Spec)
+	if err != nil {
+		logger.Error(err, "failed converge hosted zone")
+		return syncFailed(err, r.setNotReady(ctx, &hz, "ConvergeFailed", err.Error()))
+	}
+	if hz.Status.HostedZoneID != zone.ID {
+		// record a new zone before the changes which may fail below
+		hz.Status.HostedZoneID = zone.ID
+		hz.Status.NameServers = zone.NameServers
+		if err := r.Status().Update(ctx, &hz); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	hz.Status.NameServers = zone.NameServers
+
+	if hz.Spec.Private {
+		if err := r.reconcileVPCs(ctx, &hz, dnsProvider); err != nil {
+			logger.Error(err, "failed associate vpcs")
+			return syncFailed(err, r.setNotReady(ctx, &hz, "VPCAssociationFailed", err.Error()))
+		}
+	}
+
+	if err := r.reconcileDelegation(ctx, &hz); err != nil {
+		logger.Error(err, "failed delegate hosted zone")
+		return syncFailed(err, r.setNotReady(ctx, &hz, "DelegationFailed", err.Error()))
+	}
+
+	setHostedZoneCondition(&hz, dnsv1alpha1.HostedZoneConditionReady, metav1.ConditionTrue, "Synced", "hosted zone is in sync with provider")
+	if err := r.Status().Update(ctx, &hz); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// reconcileVPCs associates the private zone with the VPCs of the spec and
+// disassociates the others.
+func (r *HostedZoneReconciler) reconcileVPCs(ctx context.Context, hz *dnsv1alpha1.HostedZone, dnsProvider provider.DNSProvider) error {
+	vpcProvider, err := provider.AsVPCAssociationProvider(dnsProvider)
+	if err != nil {
+		return err
+	}
+	associations, err := vpcAssociations(ctx, r.Client, hz.Namespace, hz.Spec.VPCs)
+	if err != nil {
+		return err
+	}
+	return provider.ConvergeVPCAssociations(ctx, vpcProvider, hz.Status.HostedZoneID, associations)
+}
+
+// reconcileDelegation writes the NS records of the zone into the parent zone,
+// and removes them from the previous parent when the delegation changed.
+func (r *HostedZoneReconciler) reconcileDelegation(ctx context.Context, hz *dnsv1alpha1.HostedZone) error {
+	delegation := hz.Spec.Delegation
+
+	if prev := hz.Status.DelegationProviderRef; prev != "" && (delegation == nil || delegation.ProviderRef != prev) {
+		if err := r.undelegate(ctx, hz); err != nil {
+			return err
+		}
+		hz.Status.DelegationProviderRef = ""
+	}
+	if delegation == nil {
+		meta.RemoveStatusCondition(&hz.Status.Conditions, dnsv1alpha1.HostedZoneConditionDelegated)
+		return nil
+	}
+
+	parent, err := r.newProvider(ctx, hz.Namespace, delegation.ProviderRef)
+	if err != nil {
+		setHostedZoneCondition(hz, dnsv1alpha1.HostedZoneConditionDelegated, metav1.ConditionFalse, "ParentProviderError", err.Error())
+		return err
+	}
+	owner, err := provider.DelegationOwner(hz.Spec.ZoneName, parent.ZoneName())
+	if err != nil {
+		setHostedZoneCondition(hz, dnsv1alpha1.HostedZoneConditionDelegated, metav1.ConditionFalse, "NotSubdomain", err.Error())
+		return err
+	}
+
+	hz.Status.DelegationProviderRef = delegation.ProviderRef
+	rrSpec := provider.DelegationRecord(hz.Status.NameServers, delegation.Ttl)
+	_, _, err = provider.Converge(ctx, parent, r.zoneOwnerId(hz), []string{owner}, rrSpec, provider.Refs{}, nil, hz.Annotations[dnsv1alpha1.AdoptAnnotation] == "true")
+	if err != nil {
+		setHostedZoneCondition(hz, dnsv1alpha1.HostedZoneConditionDelegated, metav1.ConditionFalse, "ConvergeFailed", err.Error())
+		return err
+	}
+	setHostedZoneCondition(hz, dnsv1alpha1.HostedZoneConditionDelegated, metav1.ConditionTrue, "Delegated", fmt.Sprintf("delegated from %s", parent.ZoneName()))
+	return nil
+}
+
+// undelegate removes the NS records of the zone from the parent zone they were written to.
+func (r *HostedZoneReconciler) undelegate(ctx context.Context, hz *dnsv1alpha1.HostedZone) error {
+	parent, err := r.newProvider(ctx, hz.Namespace, hz.Status.DelegationProviderRef)
+	if apierrors.IsNotFound(err) {
+		// the provider is kept while zones reference it, so it was removed by force
+		return &provider.TerminalError{Err: fmt.Errorf("parent provider %s not found, can not remove the NS records of the delegation", hz.Status.DelegationProviderRef)}
+	}
+	if err != nil {
+		return err
+	}
+	owner, err := provider.DelegationOwner(hz.Spec.ZoneName, parent.ZoneName())
+	if err != nil {
+		return err
+	}
+	rrSpec := provider.DelegationRecord(hz.Status.NameServers, nil)
+	return provider.Delete(ctx, parent, r.zoneOwnerId(hz), []string{owner}, rrSpec, nil)
+}
+
+func (r *HostedZoneReconciler) reconcileDelete(ctx context.Context, hz *dnsv1alpha1.HostedZone) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(hz, finalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	if hz.Spec.DeletionPolicy != dnsv1alpha1.DeletionPolicyRetain {
+		if err := r.deleteZone(ctx, hz); err != nil {
+			logger.Error(err, "failed delete hosted zone")
+			if serr := r.setNotReady(ctx, hz, "DeleteFailed", err.Error()); serr != nil {
+				logger.Error(serr, "unable to update HostedZone status")
+			}
+			return ctrl.Result{}, err
+		}
+	}
+
+	return removeFinalizer(ctx, r, hz)
+}
+
+// deleteZone removes the delegation and deletes the zone. The zone can only be
+// deleted after the ResourceRecords in it are removed.
+func (r *HostedZoneReconciler) deleteZone(ctx context.Context, hz *dnsv1alpha1.HostedZone) error {
+	if hz.Status.DelegationProviderRef != "" {
+		if err := r.undelegate(ctx, hz); err != nil {
+			return err
+		}
+	}
+	if hz.Status.HostedZoneID == "" {
+		return nil
+	}
+
+	dnsProvider, err := r.newProvider(ctx, hz.Namespace, hz.Spec.ProviderRef)
+	if apierrors.IsNotFound(err) {
+		// the provider is kept while zones reference it, so it was removed by force
+		return &provider.TerminalError{Err: fmt.Errorf("provider %s not found, set deletionPolicy to %s to leave the hosted zone", hz.Spec.ProviderRef, dnsv1alpha1.DeletionPolicyRetain)}
+	}
+	if err != nil {
+		return err
+	}
+	hzProvider, err := provider.AsHostedZoneProvider(dnsProvider)
+	if err != nil {
+		return err
+	}
+	return hzProvider.DeleteHostedZone(ctx, hz.Status.HostedZoneID)
+}
+
+// newProvider builds the backend of the Provider in the namespace.
+func (r *HostedZoneReconciler) newProvider(ctx context.Context, namespace, providerRef string) (provider.DNSProvider, error) {
+	var p dnsv1alpha1.Provider
+	if err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: providerRef}, &p); err != nil {
+		return nil, err
+	}
+	return provider.New(ctx, &p, r.Client)
+}
+
+// zoneOwnerId identifies the controller instance and the HostedZone in the
+// owner TXT records of the delegation.
+func (r *HostedZoneReconciler) zoneOwnerId(hz *dnsv1alpha1.HostedZone) string {
+	return fmt.Sprintf("%s/%s/hostedzone/%s", r.OwnerID, hz.Namespace, hz.Name)
+}
+
+// setNotReady records the reason why the zone could not be synced.
+func (r *HostedZoneReconciler) setNotReady(ctx context.Context, hz *dnsv1alpha1.HostedZone, reason, message string) error {
+	setHostedZoneCondition(hz, dnsv1alpha1.HostedZoneConditionReady, metav1.ConditionFalse, reason, message)
+	return r.Status().Update(ctx, hz)
+}
+
+func setHostedZoneCondition(hz *dnsv1alpha1.HostedZone, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	setCondition(&hz.Status.Conditions, &hz.Status.ObservedGeneration, hz.Generation, conditionType, status, reason, message)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *HostedZoneReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &dnsv1alpha1.HostedZone{}, providerField, func(rawObj client.Object) []string {
+		return []string{rawObj.(*dnsv1alpha1.HostedZone).Spec.ProviderRef}
+	}); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &dnsv1alpha1.HostedZone{}, delegationProviderField, func(rawObj client.Object) []string {
+		hz := rawObj.(*dnsv1alpha1.HostedZone)
+		if hz.Status.DelegationProviderRef == "" {
+			return nil
+		}
+		return []string{hz.Status.DelegationProviderRef}
+	}); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&dnsv1alpha1.HostedZone{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
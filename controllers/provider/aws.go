@@ -3,7 +3,6 @@ package provider
 import (
 	"context"
 	"fmt"
-	"reflect"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -19,62 +18,50 @@ import (
 	dnsv1alpha1 "github.com/ch1aki/dns-rr/api/v1alpha1"
 )
 
-const recordOwnerPrefix = "dns-rr-owner: "
-
 type Route53Provider struct {
-	hostedZoneId string
-	client       Route53API
-}
-
-type endpoint struct {
-	// The Dns Name
-	dnsName string
-	// The type of DNS Record
-	class string
-
-	// The value of DNS Record
-	rdata string
-
-	// The TTL of DNS Record
-	ttl int64
-
-	// The Id of DNS Record
-	id string
-
-	// The owner of DNS Record
-	resourceOwner string
-
-	// The Record weighte
-	weight *int64
-
-	// The flag of Alias Record
-	isAlias bool
+	hostedZoneId   string
+	hostedZoneName string
+	client         Route53API
 
-	// The alias target of DNS Record
-	aliasTarget aliasOpts
+	// identity is the Provider and its credentials. Only changes of the same
+	// identity are submitted together with its client.
+	identity string
 }
 
-type aliasOpts struct {
-	dnsName                   string
-	hostedZoneId              string
-	evaluateAliasTargetHealth bool
+var _ DNSProvider = &Route53Provider{}
+
+func init() {
+	Register("route53", func(spec *dnsv1alpha1.ProviderSpec) bool {
+		return spec.Route53 != nil
+	}, func(ctx context.Context, p *dnsv1alpha1.Provider, c client.Client) (DNSProvider, error) {
+		r53, err := Route53Provider{}.NewClient(ctx, p, c)
+		if err != nil {
+			return nil, err
+		}
+		return r53, nil
+	})
 }
 
 func (r Route53Provider) NewClient(ctx context.Context, provider *dnsv1alpha1.Provider, c client.Client) (*Route53Provider, error) {
 	var optFns []func(*config.LoadOptions) error
 
+	identity := []string{string(provider.UID), provider.Spec.Route53.Region}
+
 	// secret ref option
+	var secretVersion string
 	if provider.Spec.Route53.Auth.SecretRef != nil {
-		cred, err := credFromSecretRef(ctx, provider, c)
+		cred, version, err := credFromSecretRef(ctx, provider.Namespace, provider.Spec.Route53.Auth.SecretRef, c)
 		if err != nil {
 			return nil, err
 		}
+		secretVersion = version
 		optFns = append(optFns, config.WithCredentialsProvider(cred))
+		identity = append(identity, cred.Value.AccessKeyID, cred.Value.SecretAccessKey)
 	}
 
 	// region option
 	if region := provider.Spec.Route53.Region; region == "" {
-		return nil, fmt.Errorf("route53 provider require region")
+		return nil, &TerminalError{Err: fmt.Errorf("route53 provider require region")}
 	} else {
 		optFns = append(optFns, config.WithRegion(region))
 	}
@@ -84,208 +71,465 @@ func (r Route53Provider) NewClient(ctx context.Context, provider *dnsv1alpha1.Pr
 		return nil, fmt.Errorf("load config error: %w", err)
 	}
 
+	// assume role option, chained from the credentials above
+	if provider.Spec.Route53.Auth.AssumeRole != nil {
+		cfg.Credentials = assumeRoleCredentialsProvider(provider, cfg, secretVersion)
+		assumeRole := provider.Spec.Route53.Auth.AssumeRole
+		identity = append(identity, assumeRole.RoleARN, assumeRole.ExternalID, sessionName(provider), assumeRole.STSEndpoint)
+	}
+
+	api := route53.NewFromConfig(cfg)
+	hostedZoneId, err := resolveHostedZoneID(ctx, api, provider)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Route53Provider{
-		hostedZoneId: provider.Spec.Route53.HostedZoneID,
-		client:       route53.NewFromConfig(cfg),
+		hostedZoneId:   hostedZoneId,
+		hostedZoneName: provider.Spec.Route53.HostedZoneName,
+		client:         api,
+		identity:       fingerprintHash(identity),
 	}, nil
 }
 
-func (p Route53Provider) Converge(ctx context.Context, zoneId string, zoneName string, owners []string, rrSpec dnsv1alpha1.ResourceRecordSpec) error {
-	// build desired endpoint
-	desired := endpoint{
-		class: rrSpec.Class,
-		ttl:   int64(rrSpec.Ttl),
-	}
-	if rrSpec.IsAlias {
-		desired.isAlias = true
-		desired.aliasTarget = aliasOpts{
-			dnsName:                   rrSpec.AliasTarget.Record,
-			hostedZoneId:              rrSpec.AliasTarget.HostedZoneID,
-			evaluateAliasTargetHealth: rrSpec.AliasTarget.EvaluateTargetHealth,
+// resolveHostedZoneID returns the configured hosted zone ID, the ID cached in
+// the status for the observed generation, or looks the zone up by name.
+func resolveHostedZoneID(ctx context.Context, api Route53API, provider *dnsv1alpha1.Provider) (string, error) {
+	spec := provider.Spec.Route53
+	if spec.HostedZoneID != "" {
+		return spec.HostedZoneID, nil
+	}
+	if provider.Status.HostedZoneID != "" && provider.Status.ObservedGeneration == provider.Generation {
+		return provider.Status.HostedZoneID, nil
+	}
+	return lookupHostedZone(ctx, api, spec.HostedZoneName, spec.ZoneType, spec.VPCHint)
+}
+
+// hostedZonesByName returns the hosted zones with the name.
+func hostedZonesByName(ctx context.Context, api Route53API, name string) ([]types.HostedZone, error) {
+	dnsName := strings.TrimSuffix(name, ".") + "."
+	params := &route53.ListHostedZonesByNameInput{DNSName: aws.String(dnsName)}
+	var zones []types.HostedZone
+	for {
+		output, err := api.ListHostedZonesByName(ctx, params)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list hosted zones named %s", name)
 		}
-	} else {
-		desired.rdata = rrSpec.Rdata
+		// zones are sorted by name, so the first other name ends the matches
+		for _, hz := range output.HostedZones {
+			if !strings.EqualFold(aws.ToString(hz.Name), dnsName) {
+				return zones, nil
+			}
+			zones = append(zones, hz)
+		}
+		if !output.IsTruncated {
+			return zones, nil
+		}
+		params.DNSName = output.NextDNSName
+		params.HostedZoneId = output.NextHostedZoneId
+	}
+}
+
+// lookupHostedZone finds the ID of the hosted zone with the name. The zone
+// type and the VPC hint narrow down the zones of the same name, and more than
+// one remaining zone is an ambiguous configuration.
+func lookupHostedZone(ctx context.Context, api Route53API, name string, zoneType dnsv1alpha1.ZoneType, vpcHint string) (string, error) {
+	zones, err := hostedZonesByName(ctx, api, name)
+	if err != nil {
+		return "", err
+	}
+	var candidates []string
+	for _, hz := range zones {
+		private := hz.Config != nil && hz.Config.PrivateZone
+		if zoneType == dnsv1alpha1.ZoneTypePublic && private || zoneType == dnsv1alpha1.ZoneTypePrivate && !private {
+			continue
+		}
+		if vpcHint != "" && !private {
+			continue
+		}
+		candidates = append(candidates, strings.TrimPrefix(aws.ToString(hz.Id), "/hostedzone/"))
 	}
 
-	if rrSpec.Weight != nil {
-		desired.weight = rrSpec.Weight
+	if vpcHint != "" {
+		var associated []string
+		for _, id := range candidates {
+			output, err := api.GetHostedZone(ctx, &route53.GetHostedZoneInput{Id: aws.String(id)})
+			if err != nil {
+				return "", errors.Wrapf(err, "failed to get hosted zone %s", id)
+			}
+			for _, vpc := range output.VPCs {
+				if aws.ToString(vpc.VPCId) == vpcHint {
+					associated = append(associated, id)
+					break
+				}
+			}
+		}
+		candidates = associated
 	}
-	if rrSpec.Id != nil {
-		desired.id = *rrSpec.Id
+
+	switch len(candidates) {
+	case 0:
+		return "", &TerminalError{Err: errors.Wrapf(ErrZoneNotFound, "no hosted zone named %s", name)}
+	case 1:
+		return candidates[0], nil
+	default:
+		return "", &TerminalError{Err: errors.Wrapf(ErrZoneAmbiguous, "hosted zones %s are named %s, set zoneType, vpcHint or hostedZoneID", strings.Join(candidates, ", "), name)}
 	}
+}
 
-	// get actual endpoints
-	currentRecords, err := p.records(ctx, zoneId, zoneName, owners, rrSpec.Class, rrSpec.Id)
+// ZoneName returns the name of the hosted zone.
+func (p Route53Provider) ZoneName() string {
+	return p.hostedZoneName
+}
+
+// Zone returns the hosted zone and checks that its name is the configured one.
+func (p Route53Provider) Zone(ctx context.Context) (*Zone, error) {
+	output, err := p.client.GetHostedZone(ctx, &route53.GetHostedZoneInput{Id: aws.String(p.hostedZoneId)})
 	if err != nil {
-		return err
+		return nil, errors.Wrapf(err, "failed to get hosted zone %s", p.hostedZoneId)
+	}
+
+	zone := &Zone{
+		ID:   strings.TrimPrefix(aws.ToString(output.HostedZone.Id), "/hostedzone/"),
+		Name: aws.ToString(output.HostedZone.Name),
 	}
+	if output.HostedZone.Config != nil {
+		zone.Private = output.HostedZone.Config.PrivateZone
+	}
+
+	if !zone.HasName(p.hostedZoneName) {
+		return nil, &TerminalError{Err: errors.Wrapf(ErrZoneMismatch, "hosted zone %s is named %s, not %s", zone.ID, zone.Name, p.hostedZoneName)}
+	}
+	return zone, nil
+}
 
-	// evalute differences
-	changes := diff(owners, zoneName, desired, currentRecords)
+// Records returns the live records of the owners in the hosted zone and the
+// TXT records which mark their owners.
+func (p Route53Provider) Records(ctx context.Context, owners []string, class string, id *string) (map[string]Endpoint, map[string]Endpoint, error) {
+	return p.records(ctx, p.hostedZoneId, p.hostedZoneName, owners, class, id)
+}
+
+// ApplyChanges submits the changes together with the changes of other
+// reconciles of the hosted zone with the same Provider and credentials as
+// one change batch.
+func (p Route53Provider) ApplyChanges(ctx context.Context, changes []Change) (string, error) {
+	return route53Batcher.Submit(ctx, p.identity+"/"+p.hostedZoneId, changes, p.changeResourceRecordSets)
+}
+
+func (p Route53Provider) changeResourceRecordSets(ctx context.Context, changes []Change) (string, error) {
+	changeRrsInput := route53.ChangeResourceRecordSetsInput{
+		HostedZoneId: aws.String(p.hostedZoneId),
+		ChangeBatch: &types.ChangeBatch{
+			Changes: route53Changes(changes),
+		},
+	}
+	output, err := p.client.ChangeResourceRecordSets(ctx, &changeRrsInput)
+	if err != nil {
+		return "", errors.Wrapf(err, "failes to change resource records set for zone %s", p.hostedZoneId)
+	}
+	if output.ChangeInfo == nil {
+		return "", nil
+	}
+	return aws.ToString(output.ChangeInfo.Id), nil
+}
+
+// ChangeInSync reports whether the change has the status INSYNC.
+func (p Route53Provider) ChangeInSync(ctx context.Context, changeId string) (bool, error) {
+	output, err := p.client.GetChange(ctx, &route53.GetChangeInput{Id: aws.String(changeId)})
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get change %s", changeId)
+	}
+	return output.ChangeInfo != nil && output.ChangeInfo.Status == types.ChangeStatusInsync, nil
+}
+
+// Capabilities returns the record features supported by Route53.
+func (p Route53Provider) Capabilities() Capabilities {
+	return Capabilities{
+		Alias:            true,
+		Weighted:         true,
+		Failover:         true,
+		Latency:          true,
+		Geolocation:      true,
+		GeoProximity:     true,
+		MultiValueAnswer: true,
+		CidrRouting:      true,
+	}
+}
 
-	// converge
-	if 0 < len(changes) {
-		changeRrsInput := route53.ChangeResourceRecordSetsInput{
-			HostedZoneId: &zoneId,
-			ChangeBatch: &types.ChangeBatch{
-				Changes: changes,
+func route53Changes(changes []Change) []types.Change {
+	r53Changes := make([]types.Change, 0, len(changes))
+	for _, c := range changes {
+		r53Changes = append(r53Changes, newChange(types.ChangeAction(c.Action), c.Endpoint))
+	}
+	return r53Changes
+}
+
+func newChange(action types.ChangeAction, ep Endpoint) types.Change {
+	var c types.Change
+	if ep.IsAlias {
+		c = types.Change{
+			ResourceRecordSet: &types.ResourceRecordSet{
+				Name: aws.String(ep.DNSName),
+				Type: types.RRType(ep.Class),
+				AliasTarget: &types.AliasTarget{
+					DNSName:              aws.String(ep.AliasTarget.DNSName),
+					HostedZoneId:         aws.String(ep.AliasTarget.HostedZoneId),
+					EvaluateTargetHealth: ep.AliasTarget.EvaluateTargetHealth,
+				},
 			},
 		}
-		if _, err := p.client.ChangeResourceRecordSets(ctx, &changeRrsInput); err != nil {
-			return errors.Wrapf(err, "failes to change resource records set for zone %s", zoneId)
+	} else {
+		c = types.Change{
+			ResourceRecordSet: &types.ResourceRecordSet{
+				Name:            aws.String(ep.DNSName),
+				Type:            types.RRType(ep.Class),
+				TTL:             aws.Int64(ep.Ttl),
+				ResourceRecords: resourceRecords(ep.Rdata),
+			},
 		}
 	}
-	return nil
-}
 
-func diff(owners []string, zoneName string, desiredEp endpoint, actualEps map[string]endpoint) []types.Change {
-	changes := make([]types.Change, 0)
+	if ep.Routed() {
+		c.ResourceRecordSet.SetIdentifier = aws.String(ep.Id)
+	}
 
-	for _, owner := range owners {
-		// build changes
-		fqdn := buildFQDN(owner, zoneName)
-		desiredEp.dnsName = fqdn
-		var c types.Change
-		if desiredEp.isAlias {
-			c = types.Change{
-				ResourceRecordSet: &types.ResourceRecordSet{
-					Name: aws.String(fqdn),
-					Type: types.RRType(desiredEp.class),
-					AliasTarget: &types.AliasTarget{
-						DNSName:              &desiredEp.aliasTarget.dnsName,
-						HostedZoneId:         &desiredEp.aliasTarget.hostedZoneId,
-						EvaluateTargetHealth: desiredEp.aliasTarget.evaluateAliasTargetHealth,
-					},
-				},
-			}
-		} else {
-			c = types.Change{
-				ResourceRecordSet: &types.ResourceRecordSet{
-					Name:            aws.String(fqdn),
-					Type:            types.RRType(desiredEp.class),
-					TTL:             &desiredEp.ttl,
-					ResourceRecords: []types.ResourceRecord{{Value: &desiredEp.rdata}},
-				},
-			}
+	// weighted record
+	if ep.Weight != nil {
+		c.ResourceRecordSet.Weight = ep.Weight
+	}
+
+	// failover record
+	if ep.Failover != "" {
+		c.ResourceRecordSet.Failover = types.ResourceRecordSetFailover(ep.Failover)
+	}
+	if ep.HealthCheckId != "" {
+		c.ResourceRecordSet.HealthCheckId = aws.String(ep.HealthCheckId)
+	}
+
+	// multivalue answer record
+	if ep.MultiValueAnswer {
+		c.ResourceRecordSet.MultiValueAnswer = aws.Bool(true)
+	}
+
+	// latency record
+	if ep.Region != "" {
+		c.ResourceRecordSet.Region = types.ResourceRecordSetRegion(ep.Region)
+	}
+
+	// geolocation record
+	if ep.GeoLocation != (GeoOpts{}) {
+		c.ResourceRecordSet.GeoLocation = &types.GeoLocation{
+			ContinentCode:   optionalString(ep.GeoLocation.ContinentCode),
+			CountryCode:     optionalString(ep.GeoLocation.CountryCode),
+			SubdivisionCode: optionalString(ep.GeoLocation.SubdivisionCode),
 		}
+	}
 
-		// weighted record
-		if desiredEp.weight != nil {
-			c.ResourceRecordSet.SetIdentifier = &desiredEp.id
-			c.ResourceRecordSet.Weight = desiredEp.weight
+	// geoproximity record
+	if gp := ep.GeoProximity; gp != (GeoProximityOpts{}) {
+		location := &types.GeoProximityLocation{AWSRegion: optionalString(gp.AWSRegion)}
+		if gp.Latitude != "" || gp.Longitude != "" {
+			location.Coordinates = &types.Coordinates{
+				Latitude:  aws.String(gp.Latitude),
+				Longitude: aws.String(gp.Longitude),
+			}
 		}
+		if gp.Bias != 0 {
+			location.Bias = aws.Int32(gp.Bias)
+		}
+		c.ResourceRecordSet.GeoProximityLocation = location
+	}
 
-		// evaluate difference
-		if _, exist := actualEps[owner]; !exist {
-			// レコードが存在しなかった場合
-			c.Action = types.ChangeActionCreate
-			changes = append(changes, c)
-		} else if !reflect.DeepEqual(desiredEp, actualEps[owner]) {
-			// 値が異なる場合
-			c.Action = types.ChangeActionUpsert
-			changes = append(changes, c)
+	// IP-based record
+	if ep.CidrRouting != (CidrOpts{}) {
+		c.ResourceRecordSet.CidrRoutingConfig = &types.CidrRoutingConfig{
+			CollectionId: aws.String(ep.CidrRouting.CollectionId),
+			LocationName: aws.String(ep.CidrRouting.LocationName),
 		}
-		// TODO: delete record when delete record definition
 	}
-	return changes
+
+	c.Action = action
+	return c
+}
+
+// optionalString returns nil for an empty string.
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return aws.String(s)
 }
 
-func (p *Route53Provider) records(ctx context.Context, zoneId string, zoneName string, owners []string, recordType string, id *string) (map[string]endpoint, error) {
-	endpoints := make(map[string]endpoint, len(owners))
+func resourceRecords(values []string) []types.ResourceRecord {
+	rrs := make([]types.ResourceRecord, len(values))
+	for i, v := range values {
+		rrs[i] = types.ResourceRecord{Value: aws.String(v)}
+	}
+	return rrs
+}
+
+func (p *Route53Provider) records(ctx context.Context, zoneId string, zoneName string, owners []string, recordType string, id *string) (map[string]Endpoint, map[string]Endpoint, error) {
+	var sid string
+	if id != nil {
+		sid = *id
+	}
+	endpoints := make(map[string]Endpoint, len(owners))
+	ownerEndpoints := make(map[string]Endpoint, len(owners))
 	for _, owner := range owners {
 		fqdn := buildFQDN(owner, zoneName)
-		// owner から始まるrecordsetをリスト
-		params := &route53.ListResourceRecordSetsInput{
-			HostedZoneId:    &zoneId,
-			StartRecordName: aws.String(fqdn),
+		ownerFqdn := buildFQDN(ownerRecordName(owner, recordType, sid), zoneName)
+		rrsets, txts, err := p.listRecordSets(ctx, zoneId, fqdn, ownerFqdn, types.RRType(recordType))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// 一致するIDのレコードだけを対象にする
+		for _, r := range rrsets {
+			if aws.ToString(r.SetIdentifier) != aws.ToString(id) {
+				continue
+			}
+			endpoints[owner] = newEndpointFromRecordSet(fqdn, r)
+			break
+		}
+		for _, r := range txts {
+			if r.SetIdentifier == nil {
+				ownerEndpoints[owner] = newEndpointFromRecordSet(ownerFqdn, r)
+				break
+			}
 		}
+	}
+	return endpoints, ownerEndpoints, nil
+}
+
+// listRecordSets returns the record sets with the name and type and the TXT
+// record sets at ownerFqdn, following the pagination of
+// ListResourceRecordSets. The owner record is a child of fqdn, so both are
+// read in one listing.
+func (p *Route53Provider) listRecordSets(ctx context.Context, zoneId string, fqdn string, ownerFqdn string, recordType types.RRType) ([]types.ResourceRecordSet, []types.ResourceRecordSet, error) {
+	params := &route53.ListResourceRecordSetsInput{
+		HostedZoneId:    aws.String(zoneId),
+		StartRecordName: aws.String(fqdn),
+		StartRecordType: recordType,
+	}
+
+	fqdn = strings.ToLower(fqdn)
+	ownerFqdn = strings.ToLower(ownerFqdn)
+	var rrsets, txts []types.ResourceRecordSet
+	for {
 		output, err := p.client.ListResourceRecordSets(ctx, params)
 		if err != nil {
-			return nil, errors.Wrapf(err, "failed to list resource records sets for zone %s", zoneId)
+			return nil, nil, errors.Wrapf(err, "failed to list resource records sets for zone %s", zoneId)
 		}
 
-		// 一致するレコードを検索
-		ep := endpoint{
-			dnsName: fqdn,
-			class:   recordType,
-		}
+		// record sets are sorted by name with the labels reversed and then
+		// by type, so the first one after the owner record ends the listing.
 		for _, r := range output.ResourceRecordSets {
-			if *r.Name == fqdn {
-				if r.Type == types.RRType(recordType) {
-					// Set rdata or alias target value
-					if r.AliasTarget != nil {
-						ep.aliasTarget.dnsName = *r.AliasTarget.DNSName
-						ep.aliasTarget.hostedZoneId = *r.AliasTarget.HostedZoneId
-						ep.aliasTarget.evaluateAliasTargetHealth = *&r.AliasTarget.EvaluateTargetHealth
-					} else {
-						// TODO: multi value レコードを考慮する
-						ep.rdata = *r.ResourceRecords[0].Value
-
-						// set ttl
-						ep.ttl = *r.TTL
-					}
-
-					// 一致するIDのレコードだけを対象にする
-					if id != nil {
-						if sid := r.SetIdentifier; sid != nil && *id == *sid {
-							ep.id = *r.SetIdentifier
-						} else {
-							break
-						}
-					}
-
-					if r.Weight != nil {
-						ep.weight = r.Weight
-					}
-
-					// TODO: owner idの考慮
-					endpoints[owner] = ep
+			name := strings.ToLower(unescapeRecordName(aws.ToString(r.Name)))
+			switch {
+			case name == fqdn:
+				if r.Type == recordType {
+					rrsets = append(rrsets, r)
 				}
-			} else {
-				break
+			case name == ownerFqdn:
+				if r.Type == types.RRTypeTxt {
+					txts = append(txts, r)
+				} else if types.RRTypeTxt < r.Type {
+					return rrsets, txts, nil
+				}
+			case listedBefore(name, fqdn, ownerFqdn):
+				// other names below fqdn, e.g. _acme-challenge
+			default:
+				return rrsets, txts, nil
 			}
 		}
+
+		if !output.IsTruncated {
+			return rrsets, txts, nil
+		}
+		params.StartRecordName = output.NextRecordName
+		params.StartRecordType = output.NextRecordType
+		params.StartRecordIdentifier = output.NextRecordIdentifier
 	}
-	return endpoints, nil
 }
 
-func buildFQDN(owner, zone string) string {
-	fqdn := fmt.Sprintf("%s.%s", owner, zone)
-	if !strings.HasSuffix(fqdn, ".") {
-		fqdn = fqdn + "."
+// listedBefore reports whether name is below parent and is listed before
+// child, a direct child of parent.
+func listedBefore(name, parent, child string) bool {
+	if !strings.HasSuffix(name, "."+parent) {
+		return false
 	}
-	return fqdn
+	rel := strings.TrimSuffix(name, "."+parent)
+	label := rel[strings.LastIndex(rel, ".")+1:]
+	return label < strings.TrimSuffix(child, "."+parent)
 }
 
-func isOwnerOfRecord(rrs types.ResourceRecordSet, ep endpoint, ownerId string) bool {
-	// validate
-	switch {
-	case *rrs.Name != ep.dnsName:
-	case rrs.Type != types.RRTypeTxt:
-	case !strings.HasPrefix(*rrs.ResourceRecords[0].Value, recordOwnerPrefix):
-	default:
-		return true
-	}
-	return false
+// unescapeRecordName decodes the wildcard which Route53 returns as \052.
+func unescapeRecordName(name string) string {
+	return strings.ReplaceAll(name, `\052`, "*")
 }
 
-func buildOwnerRecordValue(ep endpoint, ownerId string) string {
-	value := ownerId
-	if ep.id != "" {
-		value = value + "-" + ep.id
+func newEndpointFromRecordSet(fqdn string, r types.ResourceRecordSet) Endpoint {
+	ep := Endpoint{
+		DNSName:          fqdn,
+		Class:            string(r.Type),
+		Id:               aws.ToString(r.SetIdentifier),
+		Weight:           r.Weight,
+		Failover:         string(r.Failover),
+		HealthCheckId:    aws.ToString(r.HealthCheckId),
+		Region:           string(r.Region),
+		MultiValueAnswer: aws.ToBool(r.MultiValueAnswer),
+	}
+	if r.GeoLocation != nil {
+		ep.GeoLocation = GeoOpts{
+			ContinentCode:   aws.ToString(r.GeoLocation.ContinentCode),
+			CountryCode:     aws.ToString(r.GeoLocation.CountryCode),
+			SubdivisionCode: aws.ToString(r.GeoLocation.SubdivisionCode),
+		}
+	}
+	if gp := r.GeoProximityLocation; gp != nil {
+		ep.GeoProximity = GeoProximityOpts{
+			AWSRegion: aws.ToString(gp.AWSRegion),
+			Bias:      aws.ToInt32(gp.Bias),
+		}
+		if gp.Coordinates != nil {
+			ep.GeoProximity.Latitude = aws.ToString(gp.Coordinates.Latitude)
+			ep.GeoProximity.Longitude = aws.ToString(gp.Coordinates.Longitude)
+		}
+	}
+	if r.CidrRoutingConfig != nil {
+		ep.CidrRouting = CidrOpts{
+			CollectionId: aws.ToString(r.CidrRoutingConfig.CollectionId),
+			LocationName: aws.ToString(r.CidrRoutingConfig.LocationName),
+		}
+	}
+
+	// Set rdata or alias target value
+	if r.AliasTarget != nil {
+		ep.IsAlias = true
+		ep.AliasTarget.DNSName = aws.ToString(r.AliasTarget.DNSName)
+		ep.AliasTarget.HostedZoneId = aws.ToString(r.AliasTarget.HostedZoneId)
+		ep.AliasTarget.EvaluateTargetHealth = r.AliasTarget.EvaluateTargetHealth
+	} else {
+		values := make([]string, len(r.ResourceRecords))
+		for i, rr := range r.ResourceRecords {
+			values[i] = aws.ToString(rr.Value)
+		}
+		ep.Rdata = sortedValues(values)
+		ep.Ttl = aws.ToInt64(r.TTL)
 	}
-	return value
+	return ep
 }
 
-func credFromSecretRef(ctx context.Context, p *dnsv1alpha1.Provider, c client.Client) (credentials.StaticCredentialsProvider, error) {
-	secRef := p.Spec.Route53.Auth.SecretRef
+// credFromSecretRef returns the credentials in the secrets of the Provider and
+// the resource versions of the secrets.
+func credFromSecretRef(ctx context.Context, namespace string, secRef *dnsv1alpha1.AWSAuthSecretRef, c client.Client) (credentials.StaticCredentialsProvider, string, error) {
 
 	// get access key id from secret
 	var ns string
 	if secRef.AccessKeyID.Namespace != nil {
 		ns = *secRef.AccessKeyID.Namespace
 	} else {
-		ns = p.Namespace
+		ns = namespace
 	}
 	ke := client.ObjectKey{
 		Name:      secRef.AccessKeyID.Name,
@@ -294,14 +538,14 @@ func credFromSecretRef(ctx context.Context, p *dnsv1alpha1.Provider, c client.Cl
 	akSecret := v1.Secret{}
 	err := c.Get(ctx, ke, &akSecret)
 	if err != nil {
-		return credentials.StaticCredentialsProvider{}, fmt.Errorf("failed to get access key id: %w", err)
+		return credentials.StaticCredentialsProvider{}, "", fmt.Errorf("failed to get access key id: %w", err)
 	}
 
 	// get secret access key from secret
 	if secRef.SecretAccessKey.Namespace != nil {
 		ns = *secRef.SecretAccessKey.Namespace
 	} else {
-		ns = p.Namespace
+		ns = namespace
 	}
 	ke = client.ObjectKey{
 		Name:      secRef.SecretAccessKey.Name,
@@ -310,16 +554,16 @@ func credFromSecretRef(ctx context.Context, p *dnsv1alpha1.Provider, c client.Cl
 	sakSecret := v1.Secret{}
 	err = c.Get(ctx, ke, &sakSecret)
 	if err != nil {
-		return credentials.StaticCredentialsProvider{}, fmt.Errorf("failed to get secret access key: %w", err)
+		return credentials.StaticCredentialsProvider{}, "", fmt.Errorf("failed to get secret access key: %w", err)
 	}
 
 	ak := string(akSecret.Data[secRef.AccessKeyID.Key])
 	sak := string(sakSecret.Data[secRef.SecretAccessKey.Key])
 	if ak == "" {
-		return credentials.StaticCredentialsProvider{}, fmt.Errorf("missing access key id")
+		return credentials.StaticCredentialsProvider{}, "", fmt.Errorf("missing access key id")
 	}
 	if sak == "" {
-		return credentials.StaticCredentialsProvider{}, fmt.Errorf("missing secret access key")
+		return credentials.StaticCredentialsProvider{}, "", fmt.Errorf("missing secret access key")
 	}
-	return credentials.NewStaticCredentialsProvider(ak, sak, ""), nil
+	return credentials.NewStaticCredentialsProvider(ak, sak, ""), akSecret.ResourceVersion + "/" + sakSecret.ResourceVersion, nil
 }
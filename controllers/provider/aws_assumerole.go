@@ -0,0 +1,131 @@
+package provider
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"k8s.io/apimachinery/pkg/types"
+
+	dnsv1alpha1 "github.com/ch1aki/dns-rr/api/v1alpha1"
+)
+
+// assumeRoleExpiryWindow is the time before the expiry of the role session
+// credentials when they are refreshed.
+const assumeRoleExpiryWindow = time.Minute
+
+// maxSessionNameLength is the longest role session name accepted by STS.
+const maxSessionNameLength = 64
+
+// assumeRoleCredentials keeps the credentials of the assumed roles between the
+// reconciles, so that a role is only assumed again when its credentials expire.
+// The credentials of a Provider are dropped when it is deleted.
+var assumeRoleCredentials = &credentialsCaches{caches: map[types.NamespacedName]credentialsCache{}}
+
+type credentialsCaches struct {
+	mu     sync.Mutex
+	caches map[types.NamespacedName]credentialsCache
+}
+
+type credentialsCache struct {
+	fingerprint string
+	cache       *aws.CredentialsCache
+}
+
+// get returns the cached credentials of the Provider. They are replaced when
+// the fingerprint of their configuration changes.
+func (c *credentialsCaches) get(key types.NamespacedName, fingerprint string, newProvider func() aws.CredentialsProvider) *aws.CredentialsCache {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cached, ok := c.caches[key]; ok && cached.fingerprint == fingerprint {
+		return cached.cache
+	}
+	cache := aws.NewCredentialsCache(newProvider(), func(o *aws.CredentialsCacheOptions) {
+		o.ExpiryWindow = assumeRoleExpiryWindow
+	})
+	c.caches[key] = credentialsCache{fingerprint: fingerprint, cache: cache}
+	return cache
+}
+
+func (c *credentialsCaches) forget(key types.NamespacedName) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.caches, key)
+}
+
+// ForgetCredentials drops the credentials cached for the Provider, once it is
+// deleted.
+func ForgetCredentials(key types.NamespacedName) {
+	assumeRoleCredentials.forget(key)
+}
+
+// assumeRoleCredentialsProvider returns the auto-refreshing credentials of the
+// role of the Provider, assumed with the credentials of the base config.
+// baseVersion identifies the version of the base credentials, so that the role
+// is assumed again when the secrets are rotated.
+func assumeRoleCredentialsProvider(p *dnsv1alpha1.Provider, base aws.Config, baseVersion string) aws.CredentialsProvider {
+	assumeRole := p.Spec.Route53.Auth.AssumeRole
+
+	fingerprint := []string{string(p.UID), assumeRole.RoleARN, assumeRole.ExternalID, sessionName(p), assumeRole.STSEndpoint, base.Region, baseVersion}
+	if assumeRole.Duration != nil {
+		fingerprint = append(fingerprint, assumeRole.Duration.Duration.String())
+	}
+
+	key := types.NamespacedName{Namespace: p.Namespace, Name: p.Name}
+	return assumeRoleCredentials.get(key, fingerprintHash(fingerprint), func() aws.CredentialsProvider {
+		return newAssumeRoleProvider(base, assumeRole, sessionName(p))
+	})
+}
+
+// newAssumeRoleProvider returns the credentials of the role, assumed with the
+// credentials of the base config. They are not cached.
+func newAssumeRoleProvider(base aws.Config, assumeRole *dnsv1alpha1.AWSAssumeRole, session string) aws.CredentialsProvider {
+	client := sts.NewFromConfig(base, func(o *sts.Options) {
+		if assumeRole.STSEndpoint != "" {
+			o.BaseEndpoint = aws.String(assumeRole.STSEndpoint)
+		}
+	})
+	return stscreds.NewAssumeRoleProvider(client, assumeRole.RoleARN, func(o *stscreds.AssumeRoleOptions) {
+		o.RoleSessionName = session
+		if assumeRole.ExternalID != "" {
+			o.ExternalID = aws.String(assumeRole.ExternalID)
+		}
+		if assumeRole.Duration != nil {
+			o.Duration = assumeRole.Duration.Duration
+		}
+	})
+}
+
+// sessionName returns the configured role session name, or one made of the
+// namespace and the name of the Provider.
+func sessionName(p *dnsv1alpha1.Provider) string {
+	return roleSessionName(p.Spec.Route53.Auth.AssumeRole, p.Namespace, p.Name)
+}
+
+// roleSessionName returns the configured role session name, or one made of
+// the namespace and the name.
+func roleSessionName(assumeRole *dnsv1alpha1.AWSAssumeRole, namespace, name string) string {
+	if assumeRole.SessionName != "" {
+		return assumeRole.SessionName
+	}
+	name = fmt.Sprintf("dns-rr-%s-%s", namespace, name)
+	if len(name) > maxSessionNameLength {
+		name = name[:maxSessionNameLength]
+	}
+	return name
+}
+
+func fingerprintHash(values []string) string {
+	h := sha256.New()
+	for _, v := range values {
+		h.Write([]byte(v))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
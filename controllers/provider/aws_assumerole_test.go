@@ -0,0 +1,177 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	dnsv1alpha1 "github.com/ch1aki/dns-rr/api/v1alpha1"
+)
+
+const assumeRoleResponse = `<AssumeRoleResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleResult>
+    <Credentials>
+      <AccessKeyId>ASIAASSUMED</AccessKeyId>
+      <SecretAccessKey>assumed-secret</SecretAccessKey>
+      <SessionToken>assumed-token</SessionToken>
+      <Expiration>%s</Expiration>
+    </Credentials>
+    <AssumedRoleUser>
+      <Arn>arn:aws:sts::123456789012:assumed-role/dns/session</Arn>
+      <AssumedRoleId>AROAEXAMPLE:session</AssumedRoleId>
+    </AssumedRoleUser>
+  </AssumeRoleResult>
+</AssumeRoleResponse>`
+
+func TestAssumeRoleCredentialsProvider(t *testing.T) {
+	tests := []struct {
+		name       string
+		provider   dnsv1alpha1.Provider
+		wantParams url.Values
+	}{
+		{
+			name: "default session name",
+			provider: dnsv1alpha1.Provider{
+				ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "dns", UID: "uid-1"},
+				Spec: dnsv1alpha1.ProviderSpec{Route53: &dnsv1alpha1.Route53Provider{Auth: dnsv1alpha1.AWSAuth{
+					AssumeRole: &dnsv1alpha1.AWSAssumeRole{RoleARN: "arn:aws:iam::123456789012:role/dns"},
+				}}},
+			},
+			wantParams: url.Values{
+				"Action":          {"AssumeRole"},
+				"Version":         {"2011-06-15"},
+				"RoleArn":         {"arn:aws:iam::123456789012:role/dns"},
+				"RoleSessionName": {"dns-rr-dns-example"},
+				"DurationSeconds": {"900"},
+			},
+		},
+		{
+			name: "external id, session name and duration",
+			provider: dnsv1alpha1.Provider{
+				ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "dns", UID: "uid-2"},
+				Spec: dnsv1alpha1.ProviderSpec{Route53: &dnsv1alpha1.Route53Provider{Auth: dnsv1alpha1.AWSAuth{
+					AssumeRole: &dnsv1alpha1.AWSAssumeRole{
+						RoleARN:     "arn:aws:iam::123456789012:role/dns",
+						ExternalID:  "external",
+						SessionName: "session",
+						Duration:    &metav1.Duration{Duration: time.Hour},
+					},
+				}}},
+			},
+			wantParams: url.Values{
+				"Action":          {"AssumeRole"},
+				"Version":         {"2011-06-15"},
+				"RoleArn":         {"arn:aws:iam::123456789012:role/dns"},
+				"RoleSessionName": {"session"},
+				"ExternalId":      {"external"},
+				"DurationSeconds": {"3600"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []url.Values
+			var authorization string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := r.ParseForm(); err != nil {
+					t.Fatal(err)
+				}
+				requests = append(requests, r.PostForm)
+				authorization = r.Header.Get("Authorization")
+				fmt.Fprintf(w, assumeRoleResponse, time.Now().Add(time.Hour).UTC().Format(time.RFC3339))
+			}))
+			defer server.Close()
+
+			tt.provider.Spec.Route53.Auth.AssumeRole.STSEndpoint = server.URL
+			base := aws.Config{
+				Region:      "ap-northeast-1",
+				Credentials: credentials.NewStaticCredentialsProvider("AKIABASE", "base-secret", ""),
+			}
+
+			for i := 0; i < 2; i++ {
+				cred := assumeRoleCredentialsProvider(&tt.provider, base, "1/1")
+				got, err := cred.Retrieve(context.TODO())
+				if err != nil {
+					t.Fatalf("Retrieve() error = %v", err)
+				}
+				if got.AccessKeyID != "ASIAASSUMED" || got.SessionToken != "assumed-token" {
+					t.Errorf("Retrieve() = %v, want the assumed role credentials", got)
+				}
+			}
+
+			// the second reconcile uses the cached credentials
+			if len(requests) != 1 {
+				t.Fatalf("got %d AssumeRole requests, want 1", len(requests))
+			}
+			if diff := cmp.Diff(requests[0], tt.wantParams); diff != "" {
+				t.Errorf("differs: (-got +want)\n%s", diff)
+			}
+			if !strings.Contains(authorization, "Credential=AKIABASE/") {
+				t.Errorf("AssumeRole is signed with %q, want the base credentials", authorization)
+			}
+		})
+	}
+}
+
+func TestCredentialsCaches(t *testing.T) {
+	caches := &credentialsCaches{caches: map[types.NamespacedName]credentialsCache{}}
+	key := types.NamespacedName{Namespace: "dns", Name: "example"}
+	created := 0
+	newProvider := func() aws.CredentialsProvider {
+		created++
+		return credentials.NewStaticCredentialsProvider("AKIA", "secret", "")
+	}
+
+	first := caches.get(key, "v1", newProvider)
+	if got := caches.get(key, "v1", newProvider); got != first {
+		t.Errorf("expected the cached credentials for the same fingerprint")
+	}
+	if got := caches.get(key, "v2", newProvider); got == first {
+		t.Errorf("expected new credentials when the secrets are rotated")
+	}
+	caches.forget(key)
+	if len(caches.caches) != 0 {
+		t.Errorf("expected no credentials after the Provider is deleted, got %d", len(caches.caches))
+	}
+	if created != 2 {
+		t.Errorf("got %d credentials providers, want 2", created)
+	}
+}
+
+func TestSessionName(t *testing.T) {
+	tests := []struct {
+		name       string
+		objectMeta metav1.ObjectMeta
+		configured string
+		want       string
+	}{
+		{name: "configured", objectMeta: metav1.ObjectMeta{Name: "example", Namespace: "dns"}, configured: "session", want: "session"},
+		{name: "namespace and name", objectMeta: metav1.ObjectMeta{Name: "example", Namespace: "dns"}, want: "dns-rr-dns-example"},
+		{name: "truncated", objectMeta: metav1.ObjectMeta{Name: strings.Repeat("a", 63), Namespace: "dns"}, want: "dns-rr-dns-" + strings.Repeat("a", 53)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &dnsv1alpha1.Provider{
+				ObjectMeta: tt.objectMeta,
+				Spec: dnsv1alpha1.ProviderSpec{Route53: &dnsv1alpha1.Route53Provider{Auth: dnsv1alpha1.AWSAuth{
+					AssumeRole: &dnsv1alpha1.AWSAssumeRole{SessionName: tt.configured},
+				}}},
+			}
+			if got := sessionName(p); got != tt.want {
+				t.Errorf("sessionName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
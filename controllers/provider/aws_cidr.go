@@ -0,0 +1,106 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/pkg/errors"
+)
+
+// CidrCollection returns the CIDR collection with the name, or nil if it does not exist.
+func (p Route53Provider) CidrCollection(ctx context.Context, name string) (*CidrCollection, error) {
+	params := &route53.ListCidrCollectionsInput{}
+	for {
+		output, err := p.client.ListCidrCollections(ctx, params)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list cidr collections")
+		}
+		for _, c := range output.CidrCollections {
+			if aws.ToString(c.Name) == name {
+				return &CidrCollection{
+					ID:      aws.ToString(c.Id),
+					Name:    aws.ToString(c.Name),
+					Version: aws.ToInt64(c.Version),
+				}, nil
+			}
+		}
+		if output.NextToken == nil {
+			return nil, nil
+		}
+		params.NextToken = output.NextToken
+	}
+}
+
+// CreateCidrCollection creates an empty CIDR collection, or returns the
+// collection created before with the same caller reference.
+func (p Route53Provider) CreateCidrCollection(ctx context.Context, name, callerReference string) (*CidrCollection, error) {
+	output, err := p.client.CreateCidrCollection(ctx, &route53.CreateCidrCollectionInput{
+		Name:            aws.String(name),
+		CallerReference: aws.String(callerReference),
+	})
+	var ccae *types.CidrCollectionAlreadyExistsException
+	if errors.As(err, &ccae) {
+		return nil, &TerminalError{Err: errors.Wrapf(ErrCidrCollectionNotOwned, "cidr collection %s was created by another owner", name)}
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create cidr collection %s", name)
+	}
+	return &CidrCollection{
+		ID:      aws.ToString(output.Collection.Id),
+		Name:    aws.ToString(output.Collection.Name),
+		Version: aws.ToInt64(output.Collection.Version),
+	}, nil
+}
+
+// CidrBlocks returns the CIDR blocks of the collection keyed by location.
+func (p Route53Provider) CidrBlocks(ctx context.Context, id string) (map[string][]string, error) {
+	blocks := map[string][]string{}
+	params := &route53.ListCidrBlocksInput{CollectionId: aws.String(id)}
+	for {
+		output, err := p.client.ListCidrBlocks(ctx, params)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list cidr blocks of collection %s", id)
+		}
+		for _, b := range output.CidrBlocks {
+			location := aws.ToString(b.LocationName)
+			blocks[location] = append(blocks[location], aws.ToString(b.CidrBlock))
+		}
+		if output.NextToken == nil {
+			return blocks, nil
+		}
+		params.NextToken = output.NextToken
+	}
+}
+
+// ChangeCidrCollection applies the changes when the collection still has the version.
+func (p Route53Provider) ChangeCidrCollection(ctx context.Context, id string, version int64, changes []cidrChange) error {
+	r53Changes := make([]types.CidrCollectionChange, len(changes))
+	for i, c := range changes {
+		r53Changes[i] = types.CidrCollectionChange{
+			Action:       types.CidrCollectionChangeAction(c.action),
+			LocationName: aws.String(c.location),
+			CidrList:     c.cidrs,
+		}
+	}
+	_, err := p.client.ChangeCidrCollection(ctx, &route53.ChangeCidrCollectionInput{
+		Id:                aws.String(id),
+		CollectionVersion: aws.Int64(version),
+		Changes:           r53Changes,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to change cidr collection %s", id)
+	}
+	return nil
+}
+
+// DeleteCidrCollection deletes the empty collection.
+func (p Route53Provider) DeleteCidrCollection(ctx context.Context, id string) error {
+	_, err := p.client.DeleteCidrCollection(ctx, &route53.DeleteCidrCollectionInput{Id: aws.String(id)})
+	var nsc *types.NoSuchCidrCollectionException
+	if err != nil && !errors.As(err, &nsc) {
+		return errors.Wrapf(err, "failed to delete cidr collection %s", id)
+	}
+	return nil
+}
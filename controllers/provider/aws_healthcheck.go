@@ -0,0 +1,211 @@
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/pkg/errors"
+
+	dnsv1alpha1 "github.com/ch1aki/dns-rr/api/v1alpha1"
+)
+
+// healthyCheckerRatio is the percentage of checkers which have to report
+// success for Route53 to consider the endpoint healthy.
+const healthyCheckerRatio = 18
+
+// HealthCheck returns the health check with the ID, or nil if it does not exist.
+func (p Route53Provider) HealthCheck(ctx context.Context, id string) (*HealthCheck, error) {
+	output, err := p.client.GetHealthCheck(ctx, &route53.GetHealthCheckInput{HealthCheckId: aws.String(id)})
+	var nshc *types.NoSuchHealthCheck
+	if errors.As(err, &nshc) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get health check %s", id)
+	}
+	return newHealthCheck(output.HealthCheck), nil
+}
+
+// OwnedHealthCheck returns the health check whose caller reference was made
+// for the owner, or nil if there is none.
+func (p Route53Provider) OwnedHealthCheck(ctx context.Context, owner string) (*HealthCheck, error) {
+	params := &route53.ListHealthChecksInput{}
+	for {
+		output, err := p.client.ListHealthChecks(ctx, params)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list health checks")
+		}
+		for i := range output.HealthChecks {
+			if ownsCallerReference(aws.ToString(output.HealthChecks[i].CallerReference), owner) {
+				return newHealthCheck(&output.HealthChecks[i]), nil
+			}
+		}
+		if !output.IsTruncated {
+			return nil, nil
+		}
+		params.Marker = output.NextMarker
+	}
+}
+
+// CreateHealthCheck creates a health check.
+func (p Route53Provider) CreateHealthCheck(ctx context.Context, callerReference string, config healthCheckConfig) (*HealthCheck, error) {
+	output, err := p.client.CreateHealthCheck(ctx, &route53.CreateHealthCheckInput{
+		CallerReference:   aws.String(callerReference),
+		HealthCheckConfig: route53HealthCheckConfig(config),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create health check")
+	}
+	return newHealthCheck(output.HealthCheck), nil
+}
+
+// UpdateHealthCheck changes the health check when it still has the version of current.
+func (p Route53Provider) UpdateHealthCheck(ctx context.Context, current *HealthCheck, config healthCheckConfig) (*HealthCheck, error) {
+	c := route53HealthCheckConfig(config)
+	params := &route53.UpdateHealthCheckInput{
+		HealthCheckId:                aws.String(current.ID),
+		HealthCheckVersion:           aws.Int64(current.Version),
+		IPAddress:                    c.IPAddress,
+		FullyQualifiedDomainName:     c.FullyQualifiedDomainName,
+		Port:                         c.Port,
+		ResourcePath:                 c.ResourcePath,
+		SearchString:                 c.SearchString,
+		FailureThreshold:             c.FailureThreshold,
+		EnableSNI:                    c.EnableSNI,
+		Regions:                      c.Regions,
+		ChildHealthChecks:            c.ChildHealthChecks,
+		HealthThreshold:              c.HealthThreshold,
+		AlarmIdentifier:              c.AlarmIdentifier,
+		InsufficientDataHealthStatus: c.InsufficientDataHealthStatus,
+		Inverted:                     c.Inverted,
+		Disabled:                     c.Disabled,
+	}
+	// omitted elements are left unchanged, so removed ones are reset explicitly
+	if config.fqdn == "" && current.config.fqdn != "" {
+		params.ResetElements = append(params.ResetElements, types.ResettableElementNameFullyQualifiedDomainName)
+	}
+	if config.resourcePath == "" && current.config.resourcePath != "" {
+		params.ResetElements = append(params.ResetElements, types.ResettableElementNameResourcePath)
+	}
+	if len(config.regions) == 0 && len(current.config.regions) != 0 {
+		// Route53 checks from all regions again
+		params.ResetElements = append(params.ResetElements, types.ResettableElementNameRegions)
+	}
+
+	output, err := p.client.UpdateHealthCheck(ctx, params)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to update health check %s", current.ID)
+	}
+	return newHealthCheck(output.HealthCheck), nil
+}
+
+// DeleteHealthCheck deletes the health check. A health check which does not exist is skipped.
+func (p Route53Provider) DeleteHealthCheck(ctx context.Context, id string) error {
+	_, err := p.client.DeleteHealthCheck(ctx, &route53.DeleteHealthCheckInput{HealthCheckId: aws.String(id)})
+	var nshc *types.NoSuchHealthCheck
+	if err != nil && !errors.As(err, &nshc) {
+		return errors.Wrapf(err, "failed to delete health check %s", id)
+	}
+	return nil
+}
+
+// HealthStatus returns the status decided from the latest observations of the checkers.
+func (p Route53Provider) HealthStatus(ctx context.Context, id string) (dnsv1alpha1.HealthStatus, error) {
+	output, err := p.client.GetHealthCheckStatus(ctx, &route53.GetHealthCheckStatusInput{HealthCheckId: aws.String(id)})
+	if err != nil {
+		return dnsv1alpha1.HealthStatusUnknown, errors.Wrapf(err, "failed to get status of health check %s", id)
+	}
+	return healthStatus(output.HealthCheckObservations), nil
+}
+
+func healthStatus(observations []types.HealthCheckObservation) dnsv1alpha1.HealthStatus {
+	if len(observations) == 0 {
+		return dnsv1alpha1.HealthStatusUnknown
+	}
+	var healthy int
+	for _, o := range observations {
+		if o.StatusReport != nil && strings.HasPrefix(aws.ToString(o.StatusReport.Status), "Success") {
+			healthy++
+		}
+	}
+	if len(observations)*healthyCheckerRatio < healthy*100 {
+		return dnsv1alpha1.HealthStatusHealthy
+	}
+	return dnsv1alpha1.HealthStatusUnhealthy
+}
+
+func route53HealthCheckConfig(config healthCheckConfig) *types.HealthCheckConfig {
+	c := &types.HealthCheckConfig{
+		Type:                     types.HealthCheckType(config.hcType),
+		IPAddress:                optionalString(config.ipAddress),
+		FullyQualifiedDomainName: optionalString(config.fqdn),
+		ResourcePath:             optionalString(config.resourcePath),
+		SearchString:             optionalString(config.searchString),
+		Inverted:                 aws.Bool(config.inverted),
+		Disabled:                 aws.Bool(config.disabled),
+	}
+	if config.port != 0 {
+		c.Port = aws.Int32(config.port)
+	}
+	if config.requestInterval != 0 {
+		c.RequestInterval = aws.Int32(config.requestInterval)
+		c.FailureThreshold = aws.Int32(config.failureThreshold)
+		c.MeasureLatency = aws.Bool(config.measureLatency)
+		c.EnableSNI = aws.Bool(config.enableSNI)
+	}
+	for _, r := range config.regions {
+		c.Regions = append(c.Regions, types.HealthCheckRegion(r))
+	}
+	if config.hcType == string(dnsv1alpha1.HealthCheckCalculated) {
+		c.ChildHealthChecks = config.childHealthChecks
+		c.HealthThreshold = aws.Int32(config.healthThreshold)
+	}
+	if config.alarmName != "" {
+		c.AlarmIdentifier = &types.AlarmIdentifier{
+			Name:   aws.String(config.alarmName),
+			Region: types.CloudWatchRegion(config.alarmRegion),
+		}
+		c.InsufficientDataHealthStatus = types.InsufficientDataHealthStatus(config.insufficientDataHealthStatus)
+	}
+	return c
+}
+
+func newHealthCheck(hc *types.HealthCheck) *HealthCheck {
+	c := hc.HealthCheckConfig
+	config := healthCheckConfig{
+		hcType:                       string(c.Type),
+		ipAddress:                    aws.ToString(c.IPAddress),
+		fqdn:                         aws.ToString(c.FullyQualifiedDomainName),
+		port:                         aws.ToInt32(c.Port),
+		resourcePath:                 aws.ToString(c.ResourcePath),
+		searchString:                 aws.ToString(c.SearchString),
+		requestInterval:              aws.ToInt32(c.RequestInterval),
+		failureThreshold:             aws.ToInt32(c.FailureThreshold),
+		measureLatency:               aws.ToBool(c.MeasureLatency),
+		enableSNI:                    aws.ToBool(c.EnableSNI),
+		healthThreshold:              aws.ToInt32(c.HealthThreshold),
+		insufficientDataHealthStatus: string(c.InsufficientDataHealthStatus),
+		inverted:                     aws.ToBool(c.Inverted),
+		disabled:                     aws.ToBool(c.Disabled),
+	}
+	regions := make([]string, len(c.Regions))
+	for i, r := range c.Regions {
+		regions[i] = string(r)
+	}
+	config.regions = sortedValues(regions)
+	if len(c.ChildHealthChecks) != 0 {
+		config.childHealthChecks = sortedValues(c.ChildHealthChecks)
+	}
+	if c.AlarmIdentifier != nil {
+		config.alarmName = aws.ToString(c.AlarmIdentifier.Name)
+		config.alarmRegion = string(c.AlarmIdentifier.Region)
+	}
+	return &HealthCheck{
+		ID:      aws.ToString(hc.Id),
+		Version: aws.ToInt64(hc.HealthCheckVersion),
+		config:  config,
+	}
+}
@@ -0,0 +1,146 @@
+package provider
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/pkg/errors"
+)
+
+// HostedZone returns the hosted zone with the ID and its tags, or nil if it does not exist.
+func (p Route53Provider) HostedZone(ctx context.Context, id string) (*HostedZone, error) {
+	output, err := p.client.GetHostedZone(ctx, &route53.GetHostedZoneInput{Id: aws.String(id)})
+	var nshz *types.NoSuchHostedZone
+	if errors.As(err, &nshz) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get hosted zone %s", id)
+	}
+	zone := newHostedZone(output.HostedZone, output.DelegationSet)
+
+	tags, err := p.client.ListTagsForResource(ctx, &route53.ListTagsForResourceInput{
+		ResourceId:   aws.String(zone.ID),
+		ResourceType: types.TagResourceTypeHostedzone,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list tags of hosted zone %s", id)
+	}
+	if tags.ResourceTagSet != nil {
+		for _, t := range tags.ResourceTagSet.Tags {
+			zone.Tags[aws.ToString(t.Key)] = aws.ToString(t.Value)
+		}
+	}
+	return zone, nil
+}
+
+// OwnedHostedZone returns the hosted zone of the name whose caller reference
+// was made for the owner, or nil if there is none.
+func (p Route53Provider) OwnedHostedZone(ctx context.Context, name, owner string) (*HostedZone, error) {
+	zones, err := hostedZonesByName(ctx, p.client, name)
+	if err != nil {
+		return nil, err
+	}
+	for _, hz := range zones {
+		if ownsCallerReference(aws.ToString(hz.CallerReference), owner) {
+			return p.HostedZone(ctx, strings.TrimPrefix(aws.ToString(hz.Id), "/hostedzone/"))
+		}
+	}
+	return nil, nil
+}
+
+// CreateHostedZone creates a hosted zone and tags it.
+func (p Route53Provider) CreateHostedZone(ctx context.Context, callerReference string, config hostedZoneConfig) (*HostedZone, error) {
+	params := &route53.CreateHostedZoneInput{
+		Name:            aws.String(config.name),
+		CallerReference: aws.String(callerReference),
+		HostedZoneConfig: &types.HostedZoneConfig{
+			Comment:     optionalString(config.comment),
+			PrivateZone: config.private,
+		},
+		DelegationSetId: optionalString(config.delegationSetId),
+	}
+	if config.vpc != nil {
+		params.VPC = route53VPC(*config.vpc)
+	}
+	output, err := p.client.CreateHostedZone(ctx, params)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create hosted zone %s", config.name)
+	}
+	zone := newHostedZone(output.HostedZone, output.DelegationSet)
+
+	if 0 < len(config.tags) {
+		if err := p.ChangeHostedZoneTags(ctx, zone.ID, config.tags, nil); err != nil {
+			return nil, err
+		}
+		zone.Tags = config.tags
+	}
+	return zone, nil
+}
+
+// UpdateHostedZoneComment changes the comment of the hosted zone.
+func (p Route53Provider) UpdateHostedZoneComment(ctx context.Context, id, comment string) error {
+	_, err := p.client.UpdateHostedZoneComment(ctx, &route53.UpdateHostedZoneCommentInput{
+		Id:      aws.String(id),
+		Comment: aws.String(comment),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to update comment of hosted zone %s", id)
+	}
+	return nil
+}
+
+// ChangeHostedZoneTags adds or overwrites the tags in add and removes the keys in remove.
+func (p Route53Provider) ChangeHostedZoneTags(ctx context.Context, id string, add map[string]string, remove []string) error {
+	params := &route53.ChangeTagsForResourceInput{
+		ResourceId:   aws.String(id),
+		ResourceType: types.TagResourceTypeHostedzone,
+	}
+	keys := make([]string, 0, len(add))
+	for k := range add {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		params.AddTags = append(params.AddTags, types.Tag{Key: aws.String(k), Value: aws.String(add[k])})
+	}
+	if 0 < len(remove) {
+		params.RemoveTagKeys = remove
+	}
+	if _, err := p.client.ChangeTagsForResource(ctx, params); err != nil {
+		return errors.Wrapf(err, "failed to change tags of hosted zone %s", id)
+	}
+	return nil
+}
+
+// DeleteHostedZone deletes the hosted zone. A zone which does not exist is skipped.
+func (p Route53Provider) DeleteHostedZone(ctx context.Context, id string) error {
+	_, err := p.client.DeleteHostedZone(ctx, &route53.DeleteHostedZoneInput{Id: aws.String(id)})
+	var nshz *types.NoSuchHostedZone
+	if err != nil && !errors.As(err, &nshz) {
+		return errors.Wrapf(err, "failed to delete hosted zone %s", id)
+	}
+	return nil
+}
+
+func newHostedZone(hz *types.HostedZone, ds *types.DelegationSet) *HostedZone {
+	zone := &HostedZone{
+		Zone: Zone{
+			ID:   strings.TrimPrefix(aws.ToString(hz.Id), "/hostedzone/"),
+			Name: aws.ToString(hz.Name),
+		},
+		Tags: map[string]string{},
+	}
+	if hz.Config != nil {
+		zone.Private = hz.Config.PrivateZone
+		zone.Comment = aws.ToString(hz.Config.Comment)
+	}
+	if ds != nil {
+		zone.NameServers = sortedValues(ds.NameServers)
+	}
+	return zone
+}
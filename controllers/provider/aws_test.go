@@ -2,6 +2,7 @@ package provider
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -11,6 +12,10 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 
 	gomock "github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dnsv1alpha1 "github.com/ch1aki/dns-rr/api/v1alpha1"
 )
 
 func TestBuildFQDN(t *testing.T) {
@@ -42,12 +47,58 @@ func TestBuildFQDN(t *testing.T) {
 	}
 }
 
+func TestStaleOwners(t *testing.T) {
+	tests := []struct {
+		name     string
+		owners   []string
+		zoneName string
+		managed  []string
+		want     []string
+	}{
+		{
+			name:     "no managed names",
+			owners:   []string{"test"},
+			zoneName: "example.com",
+			want:     []string{},
+		},
+		{
+			name:     "all names still listed",
+			owners:   []string{"test", "www"},
+			zoneName: "example.com",
+			managed:  []string{"test.example.com.", "www.example.com."},
+			want:     []string{},
+		},
+		{
+			name:     "removed name",
+			owners:   []string{"test"},
+			zoneName: "example.com.",
+			managed:  []string{"test.example.com.", "old.sub.example.com."},
+			want:     []string{"old.sub"},
+		},
+		{
+			name:     "name in other zone",
+			owners:   []string{"test"},
+			zoneName: "example.com",
+			managed:  []string{"old.example.org."},
+			want:     []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := staleOwners(tt.owners, tt.zoneName, tt.managed)
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("differs: (-got +want)\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestDiff(t *testing.T) {
 	type args struct {
 		owners     []string
 		zoneName   string
-		desiredEp  endpoint
-		acutualEps map[string]endpoint
+		desiredEp  Endpoint
+		acutualEps map[string]Endpoint
 	}
 	tests := []struct {
 		name string
@@ -59,18 +110,18 @@ func TestDiff(t *testing.T) {
 			args: args{
 				owners:   []string{"test"},
 				zoneName: "example.com",
-				desiredEp: endpoint{
-					dnsName: "test.example.com.",
-					class:   "A",
-					rdata:   "192.0.2.1",
-					ttl:     300,
+				desiredEp: Endpoint{
+					DNSName: "test.example.com.",
+					Class:   "A",
+					Rdata:   []string{"192.0.2.1"},
+					Ttl:     300,
 				},
-				acutualEps: map[string]endpoint{
+				acutualEps: map[string]Endpoint{
 					"test": {
-						dnsName: "test.example.com.",
-						class:   "A",
-						rdata:   "192.0.2.1",
-						ttl:     300,
+						DNSName: "test.example.com.",
+						Class:   "A",
+						Rdata:   []string{"192.0.2.1"},
+						Ttl:     300,
 					},
 				},
 			},
@@ -81,13 +132,13 @@ func TestDiff(t *testing.T) {
 			args: args{
 				owners:   []string{"test"},
 				zoneName: "example.com",
-				desiredEp: endpoint{
-					dnsName: "test.example.com.",
-					class:   "A",
-					rdata:   "192.0.2.1",
-					ttl:     300,
+				desiredEp: Endpoint{
+					DNSName: "test.example.com.",
+					Class:   "A",
+					Rdata:   []string{"192.0.2.1"},
+					Ttl:     300,
 				},
-				acutualEps: map[string]endpoint{},
+				acutualEps: map[string]Endpoint{},
 			},
 			want: []types.Change{
 				{
@@ -108,18 +159,18 @@ func TestDiff(t *testing.T) {
 			args: args{
 				owners:   []string{"test"},
 				zoneName: "example.com",
-				desiredEp: endpoint{
-					dnsName: "test.example.com.",
-					class:   "A",
-					rdata:   "192.0.2.1",
-					ttl:     300,
+				desiredEp: Endpoint{
+					DNSName: "test.example.com.",
+					Class:   "A",
+					Rdata:   []string{"192.0.2.1"},
+					Ttl:     300,
 				},
-				acutualEps: map[string]endpoint{
+				acutualEps: map[string]Endpoint{
 					"test": {
-						dnsName: "test.example.com.",
-						class:   "A",
-						rdata:   "198.51.100.1",
-						ttl:     300,
+						DNSName: "test.example.com.",
+						Class:   "A",
+						Rdata:   []string{"198.51.100.1"},
+						Ttl:     300,
 					},
 				},
 			},
@@ -142,26 +193,26 @@ func TestDiff(t *testing.T) {
 			args: args{
 				owners:   []string{"test"},
 				zoneName: "example.com",
-				desiredEp: endpoint{
-					dnsName: "test.example.com.",
-					class:   "A",
-					aliasTarget: aliasOpts{
-						dnsName:                   "target.example.com.",
-						hostedZoneId:              "Z0123456789ABCDEFGHIJ",
-						evaluateAliasTargetHealth: true,
+				desiredEp: Endpoint{
+					DNSName: "test.example.com.",
+					Class:   "A",
+					AliasTarget: AliasOpts{
+						DNSName:              "target.example.com.",
+						HostedZoneId:         "Z0123456789ABCDEFGHIJ",
+						EvaluateTargetHealth: true,
 					},
-					isAlias: true,
+					IsAlias: true,
 				},
-				acutualEps: map[string]endpoint{
+				acutualEps: map[string]Endpoint{
 					"test": {
-						dnsName: "test.example.com.",
-						class:   "A",
-						aliasTarget: aliasOpts{
-							dnsName:                   "wrong.example.com.",
-							hostedZoneId:              "Z0987654321ZYXVUTSRQP",
-							evaluateAliasTargetHealth: false,
+						DNSName: "test.example.com.",
+						Class:   "A",
+						AliasTarget: AliasOpts{
+							DNSName:              "wrong.example.com.",
+							HostedZoneId:         "Z0987654321ZYXVUTSRQP",
+							EvaluateTargetHealth: false,
 						},
-						isAlias: true,
+						IsAlias: true,
 					},
 				},
 			},
@@ -185,22 +236,22 @@ func TestDiff(t *testing.T) {
 			args: args{
 				owners:   []string{"test"},
 				zoneName: "example.com",
-				desiredEp: endpoint{
-					dnsName: "test.example.com.",
-					class:   "A",
-					aliasTarget: aliasOpts{
-						dnsName:                   "target.example.com.",
-						hostedZoneId:              "Z0123456789ABCDEFGHIJ",
-						evaluateAliasTargetHealth: true,
+				desiredEp: Endpoint{
+					DNSName: "test.example.com.",
+					Class:   "A",
+					AliasTarget: AliasOpts{
+						DNSName:              "target.example.com.",
+						HostedZoneId:         "Z0123456789ABCDEFGHIJ",
+						EvaluateTargetHealth: true,
 					},
-					isAlias: true,
+					IsAlias: true,
 				},
-				acutualEps: map[string]endpoint{
+				acutualEps: map[string]Endpoint{
 					"test": {
-						dnsName: "test.example.com.",
-						class:   "A",
-						rdata:   "198.51.100.1",
-						ttl:     300,
+						DNSName: "test.example.com.",
+						Class:   "A",
+						Rdata:   []string{"198.51.100.1"},
+						Ttl:     300,
 					},
 				},
 			},
@@ -224,18 +275,18 @@ func TestDiff(t *testing.T) {
 			args: args{
 				owners:   []string{"test"},
 				zoneName: "example.com",
-				desiredEp: endpoint{
-					dnsName: "test.example.com.",
-					class:   "TXT",
-					rdata:   "test",
-					ttl:     300,
+				desiredEp: Endpoint{
+					DNSName: "test.example.com.",
+					Class:   "TXT",
+					Rdata:   []string{"test"},
+					Ttl:     300,
 				},
-				acutualEps: map[string]endpoint{
+				acutualEps: map[string]Endpoint{
 					"test": {
-						dnsName: "test.example.com.",
-						class:   "A",
-						rdata:   "198.51.100.1",
-						ttl:     300,
+						DNSName: "test.example.com.",
+						Class:   "A",
+						Rdata:   []string{"198.51.100.1"},
+						Ttl:     300,
 					},
 				},
 			},
@@ -253,27 +304,62 @@ func TestDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "diff in multi value record",
+			args: args{
+				owners:   []string{"test"},
+				zoneName: "example.com",
+				desiredEp: Endpoint{
+					DNSName: "test.example.com.",
+					Class:   "TXT",
+					Rdata:   []string{"\"google-site-verification=abc\"", "\"v=spf1 -all\""},
+					Ttl:     300,
+				},
+				acutualEps: map[string]Endpoint{
+					"test": {
+						DNSName: "test.example.com.",
+						Class:   "TXT",
+						Rdata:   []string{"\"v=spf1 -all\""},
+						Ttl:     300,
+					},
+				},
+			},
+			want: []types.Change{
+				{
+					ResourceRecordSet: &types.ResourceRecordSet{
+						Name: aws.String("test.example.com."),
+						Type: types.RRTypeTxt,
+						TTL:  aws.Int64(300),
+						ResourceRecords: []types.ResourceRecord{
+							{Value: aws.String("\"google-site-verification=abc\"")},
+							{Value: aws.String("\"v=spf1 -all\"")},
+						},
+					},
+					Action: types.ChangeActionUpsert,
+				},
+			},
+		},
 		{
 			name: "no diff in weighted record",
 			args: args{
 				owners:   []string{"test"},
 				zoneName: "example.com",
-				desiredEp: endpoint{
-					dnsName: "test.example.com.",
-					class:   "A",
-					rdata:   "198.51.100.1",
-					ttl:     300,
-					weight:  aws.Int64(10),
-					id:      "weighted-record",
-				},
-				acutualEps: map[string]endpoint{
+				desiredEp: Endpoint{
+					DNSName: "test.example.com.",
+					Class:   "A",
+					Rdata:   []string{"198.51.100.1"},
+					Ttl:     300,
+					Weight:  aws.Int64(10),
+					Id:      "weighted-record",
+				},
+				acutualEps: map[string]Endpoint{
 					"test": {
-						dnsName: "test.example.com.",
-						class:   "A",
-						rdata:   "198.51.100.1",
-						ttl:     300,
-						weight:  aws.Int64(10),
-						id:      "weighted-record",
+						DNSName: "test.example.com.",
+						Class:   "A",
+						Rdata:   []string{"198.51.100.1"},
+						Ttl:     300,
+						Weight:  aws.Int64(10),
+						Id:      "weighted-record",
 					},
 				},
 			},
@@ -284,15 +370,15 @@ func TestDiff(t *testing.T) {
 			args: args{
 				owners:   []string{"test"},
 				zoneName: "example.com",
-				desiredEp: endpoint{
-					dnsName: "test.example.com.",
-					class:   "A",
-					rdata:   "198.51.100.1",
-					ttl:     300,
-					weight:  aws.Int64(10),
-					id:      "weighted-record",
+				desiredEp: Endpoint{
+					DNSName: "test.example.com.",
+					Class:   "A",
+					Rdata:   []string{"198.51.100.1"},
+					Ttl:     300,
+					Weight:  aws.Int64(10),
+					Id:      "weighted-record",
 				},
-				acutualEps: map[string]endpoint{},
+				acutualEps: map[string]Endpoint{},
 			},
 			want: []types.Change{
 				{
@@ -315,30 +401,30 @@ func TestDiff(t *testing.T) {
 			args: args{
 				owners:   []string{"test"},
 				zoneName: "example.com",
-				desiredEp: endpoint{
-					dnsName: "test.example.com.",
-					class:   "A",
-					weight:  aws.Int64(10),
-					id:      "weighted-record",
-					isAlias: true,
-					aliasTarget: aliasOpts{
-						dnsName:                   "target.example.com.",
-						hostedZoneId:              "Z0123456789ABCDEFGHIJ",
-						evaluateAliasTargetHealth: true,
-					},
-				},
-				acutualEps: map[string]endpoint{
+				desiredEp: Endpoint{
+					DNSName: "test.example.com.",
+					Class:   "A",
+					Weight:  aws.Int64(10),
+					Id:      "weighted-record",
+					IsAlias: true,
+					AliasTarget: AliasOpts{
+						DNSName:              "target.example.com.",
+						HostedZoneId:         "Z0123456789ABCDEFGHIJ",
+						EvaluateTargetHealth: true,
+					},
+				},
+				acutualEps: map[string]Endpoint{
 					"test": {
-						dnsName: "test.example.com.",
-						class:   "A",
-						aliasTarget: aliasOpts{
-							dnsName:                   "wrong.example.com.",
-							hostedZoneId:              "Z0987654321ZYXVUTSRQP",
-							evaluateAliasTargetHealth: false,
+						DNSName: "test.example.com.",
+						Class:   "A",
+						AliasTarget: AliasOpts{
+							DNSName:              "wrong.example.com.",
+							HostedZoneId:         "Z0987654321ZYXVUTSRQP",
+							EvaluateTargetHealth: false,
 						},
-						isAlias: true,
-						id:      *aws.String("weighted-record"),
-						weight:  aws.Int64(200),
+						IsAlias: true,
+						Id:      *aws.String("weighted-record"),
+						Weight:  aws.Int64(200),
 					},
 				},
 			},
@@ -359,11 +445,156 @@ func TestDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "diff in region",
+			args: args{
+				owners:   []string{"test"},
+				zoneName: "example.com",
+				desiredEp: Endpoint{
+					DNSName: "test.example.com.",
+					Class:   "A",
+					Rdata:   []string{"192.0.2.1"},
+					Ttl:     300,
+					Id:      "latency",
+					Region:  "us-east-1",
+				},
+				acutualEps: map[string]Endpoint{
+					"test": {
+						DNSName: "test.example.com.",
+						Class:   "A",
+						Rdata:   []string{"192.0.2.1"},
+						Ttl:     300,
+						Id:      "latency",
+						Region:  "ap-northeast-1",
+					},
+				},
+			},
+			want: []types.Change{
+				{
+					ResourceRecordSet: &types.ResourceRecordSet{
+						Name:            aws.String("test.example.com."),
+						Type:            types.RRTypeA,
+						TTL:             aws.Int64(300),
+						ResourceRecords: []types.ResourceRecord{{Value: aws.String("192.0.2.1")}},
+						SetIdentifier:   aws.String("latency"),
+						Region:          types.ResourceRecordSetRegionApNortheast1,
+					},
+					Action: types.ChangeActionDelete,
+				},
+				{
+					ResourceRecordSet: &types.ResourceRecordSet{
+						Name:            aws.String("test.example.com."),
+						Type:            types.RRTypeA,
+						TTL:             aws.Int64(300),
+						ResourceRecords: []types.ResourceRecord{{Value: aws.String("192.0.2.1")}},
+						SetIdentifier:   aws.String("latency"),
+						Region:          types.ResourceRecordSetRegionUsEast1,
+					},
+					Action: types.ChangeActionCreate,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := route53Changes(diff(tt.args.owners, tt.args.zoneName, tt.args.desiredEp, tt.args.acutualEps))
+
+			// ignore option for enexported field (noSmithyDocumentSerde)
+			opts := cmpopts.IgnoreUnexported(types.Change{}, types.ResourceRecordSet{}, types.ResourceRecord{}, types.AliasTarget{})
+			if diff := cmp.Diff(got, tt.want, opts); diff != "" {
+				t.Errorf("differs: (-got +want)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDeleteChanges(t *testing.T) {
+	type args struct {
+		owners     []string
+		acutualEps map[string]Endpoint
+	}
+	tests := []struct {
+		name string
+		args args
+		want []types.Change
+	}{
+		{
+			name: "no record",
+			args: args{
+				owners:     []string{"test"},
+				acutualEps: map[string]Endpoint{},
+			},
+			want: make([]types.Change, 0),
+		},
+		{
+			name: "delete existing records",
+			args: args{
+				owners: []string{"test", "missing"},
+				acutualEps: map[string]Endpoint{
+					"test": {
+						DNSName: "test.example.com.",
+						Class:   "A",
+						Rdata:   []string{"198.51.100.1"},
+						Ttl:     300,
+					},
+				},
+			},
+			want: []types.Change{
+				{
+					ResourceRecordSet: &types.ResourceRecordSet{
+						Name: aws.String("test.example.com."),
+						Type: types.RRTypeA,
+						TTL:  aws.Int64(300),
+						ResourceRecords: []types.ResourceRecord{
+							{Value: aws.String("198.51.100.1")},
+						},
+					},
+					Action: types.ChangeActionDelete,
+				},
+			},
+		},
+		{
+			name: "delete alias weighted record",
+			args: args{
+				owners: []string{"test"},
+				acutualEps: map[string]Endpoint{
+					"test": {
+						DNSName: "test.example.com.",
+						Class:   "A",
+						AliasTarget: AliasOpts{
+							DNSName:              "target.example.com.",
+							HostedZoneId:         "Z0123456789ABCDEFGHIJ",
+							EvaluateTargetHealth: true,
+						},
+						IsAlias: true,
+						Id:      "weighted-record",
+						Weight:  aws.Int64(10),
+					},
+				},
+			},
+			want: []types.Change{
+				{
+					ResourceRecordSet: &types.ResourceRecordSet{
+						Name: aws.String("test.example.com."),
+						Type: types.RRTypeA,
+						AliasTarget: &types.AliasTarget{
+							DNSName:              aws.String("target.example.com."),
+							HostedZoneId:         aws.String("Z0123456789ABCDEFGHIJ"),
+							EvaluateTargetHealth: true,
+						},
+						SetIdentifier: aws.String("weighted-record"),
+						Weight:        aws.Int64(10),
+					},
+					Action: types.ChangeActionDelete,
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := diff(tt.args.owners, tt.args.zoneName, tt.args.desiredEp, tt.args.acutualEps)
+			got := route53Changes(deleteChanges(tt.args.owners, tt.args.acutualEps))
 
 			// ignore option for enexported field (noSmithyDocumentSerde)
 			opts := cmpopts.IgnoreUnexported(types.Change{}, types.ResourceRecordSet{}, types.ResourceRecord{}, types.AliasTarget{})
@@ -383,11 +614,12 @@ func TestRecords(t *testing.T) {
 		id         *string
 	}
 	tests := []struct {
-		name     string
-		args     args
-		beforeDo func() (Route53Provider, *gomock.Controller)
-		want     map[string]endpoint
-		wantErr  bool
+		name       string
+		args       args
+		beforeDo   func() (Route53Provider, *gomock.Controller)
+		want       map[string]Endpoint
+		wantOwners map[string]Endpoint
+		wantErr    bool
 	}{
 		{
 			name: "get matched record",
@@ -405,6 +637,7 @@ func TestRecords(t *testing.T) {
 					&route53.ListResourceRecordSetsInput{
 						HostedZoneId:    aws.String("Z0123456789ABCDEFGHIJ"),
 						StartRecordName: aws.String("test.example.com."),
+						StartRecordType: types.RRTypeA,
 					},
 				).Return(
 					&route53.ListResourceRecordSetsOutput{
@@ -427,12 +660,12 @@ func TestRecords(t *testing.T) {
 				).Times(1)
 				return Route53Provider{client: r53api, hostedZoneId: "Z0123456789ABCDEFGHIJ"}, controller
 			},
-			want: map[string]endpoint{
+			want: map[string]Endpoint{
 				"test": {
-					dnsName: "test.example.com.",
-					class:   "A",
-					rdata:   "198.51.100.1",
-					ttl:     300,
+					DNSName: "test.example.com.",
+					Class:   "A",
+					Rdata:   []string{"198.51.100.1"},
+					Ttl:     300,
 				},
 			},
 			wantErr: false,
@@ -453,6 +686,7 @@ func TestRecords(t *testing.T) {
 					&route53.ListResourceRecordSetsInput{
 						HostedZoneId:    aws.String("Z0123456789ABCDEFGHIJ"),
 						StartRecordName: aws.String("alias.example.com."),
+						StartRecordType: types.RRTypeA,
 					},
 				).Return(
 					&route53.ListResourceRecordSetsOutput{
@@ -472,15 +706,62 @@ func TestRecords(t *testing.T) {
 				).Times(1)
 				return Route53Provider{client: r53api, hostedZoneId: "Z0123456789ABCDEFGHIJ"}, controller
 			},
-			want: map[string]endpoint{
+			want: map[string]Endpoint{
 				"alias": {
-					dnsName: "alias.example.com.",
-					class:   "A",
-					aliasTarget: aliasOpts{
-						dnsName:                   "test.example.com.",
-						hostedZoneId:              "Z0123456789ABCDEFGHIJ",
-						evaluateAliasTargetHealth: true,
+					DNSName: "alias.example.com.",
+					Class:   "A",
+					AliasTarget: AliasOpts{
+						DNSName:              "test.example.com.",
+						HostedZoneId:         "Z0123456789ABCDEFGHIJ",
+						EvaluateTargetHealth: true,
+					},
+					IsAlias: true,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "get multi value record",
+			args: args{
+				zoneId:     "Z0123456789ABCDEFGHIJ",
+				zoneName:   "example.com",
+				owners:     []string{"rr"},
+				recordType: "A",
+			},
+			beforeDo: func() (Route53Provider, *gomock.Controller) {
+				controller := gomock.NewController(t)
+				r53api := NewMockRoute53API(controller)
+				r53api.EXPECT().ListResourceRecordSets(
+					context.TODO(),
+					&route53.ListResourceRecordSetsInput{
+						HostedZoneId:    aws.String("Z0123456789ABCDEFGHIJ"),
+						StartRecordName: aws.String("rr.example.com."),
+						StartRecordType: types.RRTypeA,
+					},
+				).Return(
+					&route53.ListResourceRecordSetsOutput{
+						ResourceRecordSets: []types.ResourceRecordSet{
+							{
+								Name: aws.String("rr.example.com."),
+								Type: types.RRTypeA,
+								ResourceRecords: []types.ResourceRecord{
+									{Value: aws.String("198.51.100.2")},
+									{Value: aws.String("198.51.100.1")},
+								},
+								TTL: aws.Int64(300),
+							},
+						},
 					},
+					nil,
+				).Times(1)
+				return Route53Provider{client: r53api, hostedZoneId: "Z0123456789ABCDEFGHIJ"}, controller
+			},
+			want: map[string]Endpoint{
+				"rr": {
+					DNSName: "rr.example.com.",
+					Class:   "A",
+					Rdata:   []string{"198.51.100.1", "198.51.100.2"},
+					Ttl:     300,
 				},
 			},
 			wantErr: false,
@@ -502,6 +783,7 @@ func TestRecords(t *testing.T) {
 					&route53.ListResourceRecordSetsInput{
 						HostedZoneId:    aws.String("Z0123456789ABCDEFGHIJ"),
 						StartRecordName: aws.String("weighted.example.com."),
+						StartRecordType: types.RRTypeA,
 					},
 				).Return(
 					&route53.ListResourceRecordSetsOutput{
@@ -528,14 +810,245 @@ func TestRecords(t *testing.T) {
 				).Times(1)
 				return Route53Provider{client: r53api, hostedZoneId: "Z0123456789ABCDEFGHIJ"}, controller
 			},
-			want: map[string]endpoint{
+			want: map[string]Endpoint{
 				"weighted": {
-					dnsName: "weighted.example.com.",
-					class:   "A",
-					rdata:   "198.51.100.1",
-					ttl:     300,
-					id:      "weighted-test",
-					weight:  aws.Int64(10),
+					DNSName: "weighted.example.com.",
+					Class:   "A",
+					Rdata:   []string{"198.51.100.1"},
+					Ttl:     300,
+					Id:      "weighted-test",
+					Weight:  aws.Int64(10),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "follow truncated listing",
+			args: args{
+				zoneId:     "Z0123456789ABCDEFGHIJ",
+				zoneName:   "example.com",
+				owners:     []string{"*"},
+				recordType: "A",
+				id:         aws.String("second"),
+			},
+			beforeDo: func() (Route53Provider, *gomock.Controller) {
+				controller := gomock.NewController(t)
+				r53api := NewMockRoute53API(controller)
+				gomock.InOrder(
+					r53api.EXPECT().ListResourceRecordSets(
+						context.TODO(),
+						&route53.ListResourceRecordSetsInput{
+							HostedZoneId:    aws.String("Z0123456789ABCDEFGHIJ"),
+							StartRecordName: aws.String("*.example.com."),
+							StartRecordType: types.RRTypeA,
+						},
+					).Return(
+						&route53.ListResourceRecordSetsOutput{
+							ResourceRecordSets: []types.ResourceRecordSet{
+								{
+									Name:            aws.String("\\052.example.com."),
+									Type:            types.RRTypeA,
+									ResourceRecords: []types.ResourceRecord{{Value: aws.String("198.51.100.1")}},
+									TTL:             aws.Int64(300),
+									SetIdentifier:   aws.String("first"),
+									Weight:          aws.Int64(10),
+								},
+							},
+							IsTruncated:          true,
+							NextRecordName:       aws.String("\\052.example.com."),
+							NextRecordType:       types.RRTypeA,
+							NextRecordIdentifier: aws.String("second"),
+						},
+						nil,
+					),
+					r53api.EXPECT().ListResourceRecordSets(
+						context.TODO(),
+						&route53.ListResourceRecordSetsInput{
+							HostedZoneId:          aws.String("Z0123456789ABCDEFGHIJ"),
+							StartRecordName:       aws.String("\\052.example.com."),
+							StartRecordType:       types.RRTypeA,
+							StartRecordIdentifier: aws.String("second"),
+						},
+					).Return(
+						&route53.ListResourceRecordSetsOutput{
+							ResourceRecordSets: []types.ResourceRecordSet{
+								{
+									Name:            aws.String("\\052.example.com."),
+									Type:            types.RRTypeA,
+									ResourceRecords: []types.ResourceRecord{{Value: aws.String("198.51.100.2")}},
+									TTL:             aws.Int64(300),
+									SetIdentifier:   aws.String("second"),
+									Weight:          aws.Int64(20),
+								},
+								{
+									Name:            aws.String("\\052.example.com."),
+									Type:            types.RRTypeTxt,
+									ResourceRecords: []types.ResourceRecord{{Value: aws.String("\"expected ignore\"")}},
+									TTL:             aws.Int64(300),
+								},
+							},
+						},
+						nil,
+					),
+				)
+				return Route53Provider{client: r53api, hostedZoneId: "Z0123456789ABCDEFGHIJ"}, controller
+			},
+			want: map[string]Endpoint{
+				"*": {
+					DNSName: "*.example.com.",
+					Class:   "A",
+					Rdata:   []string{"198.51.100.2"},
+					Ttl:     300,
+					Id:      "second",
+					Weight:  aws.Int64(20),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "get multivalue answer record among several identifiers",
+			args: args{
+				zoneId:     "Z0123456789ABCDEFGHIJ",
+				zoneName:   "example.com",
+				owners:     []string{"mv"},
+				recordType: "A",
+				id:         aws.String("web-2"),
+			},
+			beforeDo: func() (Route53Provider, *gomock.Controller) {
+				controller := gomock.NewController(t)
+				r53api := NewMockRoute53API(controller)
+				newRecordSet := func(id, value string) types.ResourceRecordSet {
+					return types.ResourceRecordSet{
+						Name:             aws.String("mv.example.com."),
+						Type:             types.RRTypeA,
+						ResourceRecords:  []types.ResourceRecord{{Value: aws.String(value)}},
+						TTL:              aws.Int64(60),
+						SetIdentifier:    aws.String(id),
+						MultiValueAnswer: aws.Bool(true),
+					}
+				}
+				r53api.EXPECT().ListResourceRecordSets(
+					context.TODO(),
+					&route53.ListResourceRecordSetsInput{
+						HostedZoneId:    aws.String("Z0123456789ABCDEFGHIJ"),
+						StartRecordName: aws.String("mv.example.com."),
+						StartRecordType: types.RRTypeA,
+					},
+				).Return(
+					&route53.ListResourceRecordSetsOutput{
+						ResourceRecordSets: []types.ResourceRecordSet{
+							newRecordSet("web-1", "198.51.100.1"),
+							newRecordSet("web-2", "198.51.100.2"),
+							newRecordSet("web-3", "198.51.100.3"),
+						},
+					},
+					nil,
+				).Times(1)
+				return Route53Provider{client: r53api, hostedZoneId: "Z0123456789ABCDEFGHIJ"}, controller
+			},
+			want: map[string]Endpoint{
+				"mv": {
+					DNSName:          "mv.example.com.",
+					Class:            "A",
+					Rdata:            []string{"198.51.100.2"},
+					Ttl:              60,
+					Id:               "web-2",
+					MultiValueAnswer: true,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "get owner record in the same listing",
+			args: args{
+				zoneId:     "Z0123456789ABCDEFGHIJ",
+				zoneName:   "example.com",
+				owners:     []string{"test"},
+				recordType: "A",
+			},
+			beforeDo: func() (Route53Provider, *gomock.Controller) {
+				controller := gomock.NewController(t)
+				r53api := NewMockRoute53API(controller)
+				r53api.EXPECT().ListResourceRecordSets(
+					context.TODO(),
+					&route53.ListResourceRecordSetsInput{
+						HostedZoneId:    aws.String("Z0123456789ABCDEFGHIJ"),
+						StartRecordName: aws.String("test.example.com."),
+						StartRecordType: types.RRTypeA,
+					},
+				).Return(
+					&route53.ListResourceRecordSetsOutput{
+						ResourceRecordSets: []types.ResourceRecordSet{
+							{
+								Name:            aws.String("test.example.com."),
+								Type:            types.RRTypeA,
+								ResourceRecords: []types.ResourceRecord{{Value: aws.String("198.51.100.1")}},
+								TTL:             aws.Int64(300),
+							},
+							{
+								Name:            aws.String("test.example.com."),
+								Type:            types.RRTypeTxt,
+								ResourceRecords: []types.ResourceRecord{{Value: aws.String("\"expected ignore\"")}},
+								TTL:             aws.Int64(300),
+							},
+							{
+								Name:            aws.String("_acme-challenge.test.example.com."),
+								Type:            types.RRTypeTxt,
+								ResourceRecords: []types.ResourceRecord{{Value: aws.String("\"expected ignore\"")}},
+								TTL:             aws.Int64(300),
+							},
+						},
+						IsTruncated:    true,
+						NextRecordName: aws.String("_dns-rr-a.test.example.com."),
+						NextRecordType: types.RRTypeTxt,
+					},
+					nil,
+				)
+				r53api.EXPECT().ListResourceRecordSets(
+					context.TODO(),
+					&route53.ListResourceRecordSetsInput{
+						HostedZoneId:    aws.String("Z0123456789ABCDEFGHIJ"),
+						StartRecordName: aws.String("_dns-rr-a.test.example.com."),
+						StartRecordType: types.RRTypeTxt,
+					},
+				).Return(
+					&route53.ListResourceRecordSetsOutput{
+						ResourceRecordSets: []types.ResourceRecordSet{
+							{
+								Name:            aws.String("_dns-rr-a.test.example.com."),
+								Type:            types.RRTypeTxt,
+								ResourceRecords: []types.ResourceRecord{{Value: aws.String("\"dns-rr-owner: default/ns/rr\"")}},
+								TTL:             aws.Int64(300),
+							},
+							{
+								Name:            aws.String("www.test.example.com."),
+								Type:            types.RRTypeA,
+								ResourceRecords: []types.ResourceRecord{{Value: aws.String("198.51.100.2")}},
+								TTL:             aws.Int64(300),
+							},
+						},
+						IsTruncated:    true,
+						NextRecordName: aws.String("www.test.example.com."),
+						NextRecordType: types.RRTypeAaaa,
+					},
+					nil,
+				)
+				return Route53Provider{client: r53api, hostedZoneId: "Z0123456789ABCDEFGHIJ"}, controller
+			},
+			want: map[string]Endpoint{
+				"test": {
+					DNSName: "test.example.com.",
+					Class:   "A",
+					Rdata:   []string{"198.51.100.1"},
+					Ttl:     300,
+				},
+			},
+			wantOwners: map[string]Endpoint{
+				"test": {
+					DNSName: "_dns-rr-a.test.example.com.",
+					Class:   "TXT",
+					Rdata:   []string{"\"dns-rr-owner: default/ns/rr\""},
+					Ttl:     300,
 				},
 			},
 			wantErr: false,
@@ -546,14 +1059,538 @@ func TestRecords(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			p, controller := tt.beforeDo()
 			defer controller.Finish()
-			got, err := p.records(context.TODO(), tt.args.zoneId, tt.args.zoneName, tt.args.owners, tt.args.recordType, tt.args.id)
-			opts := cmp.AllowUnexported(endpoint{}, aliasOpts{})
+			got, gotOwners, err := p.records(context.TODO(), tt.args.zoneId, tt.args.zoneName, tt.args.owners, tt.args.recordType, tt.args.id)
+			opts := cmp.AllowUnexported(Endpoint{}, AliasOpts{}, GeoOpts{}, GeoProximityOpts{}, CidrOpts{})
 			if diff := cmp.Diff(got, tt.want, opts); diff != "" {
 				t.Errorf("differs: (-got +want)\n%s", diff)
 			}
+			if diff := cmp.Diff(gotOwners, tt.wantOwners, opts, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("owner records differ: (-got +want)\n%s", diff)
+			}
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Ensure() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
+
+func TestZone(t *testing.T) {
+	tests := []struct {
+		name         string
+		beforeDo     func() (Route53Provider, *gomock.Controller)
+		want         *Zone
+		wantErr      bool
+		wantMismatch bool
+	}{
+		{
+			name: "public zone",
+			beforeDo: func() (Route53Provider, *gomock.Controller) {
+				controller := gomock.NewController(t)
+				r53api := NewMockRoute53API(controller)
+				r53api.EXPECT().GetHostedZone(
+					context.TODO(),
+					&route53.GetHostedZoneInput{Id: aws.String("Z0123456789ABCDEFGHIJ")},
+				).Return(
+					&route53.GetHostedZoneOutput{
+						HostedZone: &types.HostedZone{
+							Id:     aws.String("/hostedzone/Z0123456789ABCDEFGHIJ"),
+							Name:   aws.String("example.com."),
+							Config: &types.HostedZoneConfig{PrivateZone: false},
+						},
+					},
+					nil,
+				).Times(1)
+				return Route53Provider{client: r53api, hostedZoneId: "Z0123456789ABCDEFGHIJ", hostedZoneName: "example.com"}, controller
+			},
+			want: &Zone{ID: "Z0123456789ABCDEFGHIJ", Name: "example.com."},
+		},
+		{
+			name: "zone name mismatch",
+			beforeDo: func() (Route53Provider, *gomock.Controller) {
+				controller := gomock.NewController(t)
+				r53api := NewMockRoute53API(controller)
+				r53api.EXPECT().GetHostedZone(
+					context.TODO(),
+					&route53.GetHostedZoneInput{Id: aws.String("Z0123456789ABCDEFGHIJ")},
+				).Return(
+					&route53.GetHostedZoneOutput{
+						HostedZone: &types.HostedZone{
+							Id:   aws.String("/hostedzone/Z0123456789ABCDEFGHIJ"),
+							Name: aws.String("example.org."),
+						},
+					},
+					nil,
+				).Times(1)
+				return Route53Provider{client: r53api, hostedZoneId: "Z0123456789ABCDEFGHIJ", hostedZoneName: "example.com"}, controller
+			},
+			wantErr:      true,
+			wantMismatch: true,
+		},
+		{
+			name: "private zone",
+			beforeDo: func() (Route53Provider, *gomock.Controller) {
+				controller := gomock.NewController(t)
+				r53api := NewMockRoute53API(controller)
+				r53api.EXPECT().GetHostedZone(
+					context.TODO(),
+					&route53.GetHostedZoneInput{Id: aws.String("Z0123456789ABCDEFGHIJ")},
+				).Return(
+					&route53.GetHostedZoneOutput{
+						HostedZone: &types.HostedZone{
+							Id:     aws.String("/hostedzone/Z0123456789ABCDEFGHIJ"),
+							Name:   aws.String("example.internal."),
+							Config: &types.HostedZoneConfig{PrivateZone: true},
+						},
+					},
+					nil,
+				).Times(1)
+				return Route53Provider{client: r53api, hostedZoneId: "Z0123456789ABCDEFGHIJ", hostedZoneName: "example.internal."}, controller
+			},
+			want: &Zone{ID: "Z0123456789ABCDEFGHIJ", Name: "example.internal.", Private: true},
+		},
+		{
+			name: "no such hosted zone",
+			beforeDo: func() (Route53Provider, *gomock.Controller) {
+				controller := gomock.NewController(t)
+				r53api := NewMockRoute53API(controller)
+				r53api.EXPECT().GetHostedZone(
+					context.TODO(),
+					&route53.GetHostedZoneInput{Id: aws.String("Z0123456789ABCDEFGHIJ")},
+				).Return(nil, &types.NoSuchHostedZone{}).Times(1)
+				return Route53Provider{client: r53api, hostedZoneId: "Z0123456789ABCDEFGHIJ", hostedZoneName: "example.com"}, controller
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, controller := tt.beforeDo()
+			defer controller.Finish()
+			got, err := p.Zone(context.TODO())
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("differs: (-got +want)\n%s", diff)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Zone() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if errors.Is(err, ErrZoneMismatch) != tt.wantMismatch {
+				t.Errorf("Zone() error = %v, wantMismatch %v", err, tt.wantMismatch)
+			}
+		})
+	}
+}
+
+func TestZoneHasName(t *testing.T) {
+	tests := []struct {
+		name     string
+		zoneName string
+		arg      string
+		want     bool
+	}{
+		{name: "same name", zoneName: "example.com.", arg: "example.com.", want: true},
+		{name: "without root node", zoneName: "example.com.", arg: "Example.com", want: true},
+		{name: "different name", zoneName: "example.com.", arg: "example.org", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (Zone{Name: tt.zoneName}).HasName(tt.arg); got != tt.want {
+				t.Errorf("HasName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLookupHostedZone(t *testing.T) {
+	zones := []types.HostedZone{
+		{Id: aws.String("/hostedzone/ZPUBLIC"), Name: aws.String("example.com."), Config: &types.HostedZoneConfig{PrivateZone: false}},
+		{Id: aws.String("/hostedzone/ZPRIVATE1"), Name: aws.String("example.com."), Config: &types.HostedZoneConfig{PrivateZone: true}},
+		{Id: aws.String("/hostedzone/ZPRIVATE2"), Name: aws.String("example.com."), Config: &types.HostedZoneConfig{PrivateZone: true}},
+		{Id: aws.String("/hostedzone/ZOTHER"), Name: aws.String("example.net."), Config: &types.HostedZoneConfig{PrivateZone: false}},
+	}
+	tests := []struct {
+		name          string
+		zoneName      string
+		zoneType      dnsv1alpha1.ZoneType
+		vpcHint       string
+		beforeDo      func(r53api *MockRoute53API)
+		want          string
+		wantNotFound  bool
+		wantAmbiguous bool
+	}{
+		{
+			name:     "public zone",
+			zoneName: "example.com",
+			zoneType: dnsv1alpha1.ZoneTypePublic,
+			want:     "ZPUBLIC",
+		},
+		{
+			name:          "same name is ambiguous",
+			zoneName:      "example.com",
+			wantAmbiguous: true,
+		},
+		{
+			name:          "private zones are ambiguous",
+			zoneName:      "example.com.",
+			zoneType:      dnsv1alpha1.ZoneTypePrivate,
+			wantAmbiguous: true,
+		},
+		{
+			name:     "vpc hint selects private zone",
+			zoneName: "example.com",
+			vpcHint:  "vpc-2",
+			beforeDo: func(r53api *MockRoute53API) {
+				r53api.EXPECT().GetHostedZone(
+					context.TODO(),
+					&route53.GetHostedZoneInput{Id: aws.String("ZPRIVATE1")},
+				).Return(
+					&route53.GetHostedZoneOutput{VPCs: []types.VPC{{VPCId: aws.String("vpc-1"), VPCRegion: types.VPCRegionApNortheast1}}},
+					nil,
+				).Times(1)
+				r53api.EXPECT().GetHostedZone(
+					context.TODO(),
+					&route53.GetHostedZoneInput{Id: aws.String("ZPRIVATE2")},
+				).Return(
+					&route53.GetHostedZoneOutput{VPCs: []types.VPC{{VPCId: aws.String("vpc-2"), VPCRegion: types.VPCRegionApNortheast1}}},
+					nil,
+				).Times(1)
+			},
+			want: "ZPRIVATE2",
+		},
+		{
+			name:         "no zone of the name",
+			zoneName:     "example.jp",
+			wantNotFound: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+			r53api := NewMockRoute53API(controller)
+			dnsName := strings.TrimSuffix(tt.zoneName, ".") + "."
+			var found []types.HostedZone
+			for _, hz := range zones {
+				if aws.ToString(hz.Name) >= dnsName {
+					found = append(found, hz)
+				}
+			}
+			r53api.EXPECT().ListHostedZonesByName(
+				context.TODO(),
+				&route53.ListHostedZonesByNameInput{DNSName: aws.String(dnsName)},
+			).Return(&route53.ListHostedZonesByNameOutput{HostedZones: found}, nil).Times(1)
+			if tt.beforeDo != nil {
+				tt.beforeDo(r53api)
+			}
+
+			got, err := lookupHostedZone(context.TODO(), r53api, tt.zoneName, tt.zoneType, tt.vpcHint)
+			if got != tt.want {
+				t.Errorf("lookupHostedZone() = %v, want %v", got, tt.want)
+			}
+			if errors.Is(err, ErrZoneNotFound) != tt.wantNotFound {
+				t.Errorf("lookupHostedZone() error = %v, wantNotFound %v", err, tt.wantNotFound)
+			}
+			if errors.Is(err, ErrZoneAmbiguous) != tt.wantAmbiguous {
+				t.Errorf("lookupHostedZone() error = %v, wantAmbiguous %v", err, tt.wantAmbiguous)
+			}
+			if err != nil && !IsTerminal(err) {
+				t.Errorf("lookupHostedZone() error = %v, want terminal", err)
+			}
+		})
+	}
+}
+
+func TestResolveHostedZoneID(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider dnsv1alpha1.Provider
+		want     string
+	}{
+		{
+			name: "configured id",
+			provider: dnsv1alpha1.Provider{
+				Spec:   dnsv1alpha1.ProviderSpec{Route53: &dnsv1alpha1.Route53Provider{HostedZoneID: "ZSPEC", HostedZoneName: "example.com"}},
+				Status: dnsv1alpha1.ProviderStatus{HostedZoneID: "ZSTATUS"},
+			},
+			want: "ZSPEC",
+		},
+		{
+			name: "cached id of observed generation",
+			provider: dnsv1alpha1.Provider{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Spec:       dnsv1alpha1.ProviderSpec{Route53: &dnsv1alpha1.Route53Provider{HostedZoneName: "example.com"}},
+				Status:     dnsv1alpha1.ProviderStatus{HostedZoneID: "ZSTATUS", ObservedGeneration: 2},
+			},
+			want: "ZSTATUS",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+			got, err := resolveHostedZoneID(context.TODO(), NewMockRoute53API(controller), &tt.provider)
+			if err != nil {
+				t.Fatalf("resolveHostedZoneID() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("resolveHostedZoneID() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChangeInSync(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  types.ChangeStatus
+		err     error
+		want    bool
+		wantErr bool
+	}{
+		{
+			name:   "pending",
+			status: types.ChangeStatusPending,
+			want:   false,
+		},
+		{
+			name:   "in sync",
+			status: types.ChangeStatusInsync,
+			want:   true,
+		},
+		{
+			name:    "no such change",
+			err:     &types.NoSuchChange{Message: aws.String("not found")},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+			r53api := NewMockRoute53API(controller)
+			var output *route53.GetChangeOutput
+			if tt.err == nil {
+				output = &route53.GetChangeOutput{ChangeInfo: &types.ChangeInfo{Id: aws.String("/change/C0123456789"), Status: tt.status}}
+			}
+			r53api.EXPECT().GetChange(
+				context.TODO(),
+				&route53.GetChangeInput{Id: aws.String("/change/C0123456789")},
+			).Return(output, tt.err).Times(1)
+			p := Route53Provider{client: r53api, hostedZoneId: "Z0123456789ABCDEFGHIJ"}
+
+			got, err := p.ChangeInSync(context.TODO(), "/change/C0123456789")
+			if got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ChangeInSync() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewChange(t *testing.T) {
+	tests := []struct {
+		name string
+		ep   Endpoint
+		want types.Change
+	}{
+		{
+			name: "weighted record",
+			ep:   Endpoint{DNSName: "test.example.com.", Class: "A", Rdata: []string{"192.0.2.1"}, Ttl: 300, Id: "blue", Weight: aws.Int64(10)},
+			want: types.Change{
+				Action: types.ChangeActionUpsert,
+				ResourceRecordSet: &types.ResourceRecordSet{
+					Name:            aws.String("test.example.com."),
+					Type:            types.RRTypeA,
+					TTL:             aws.Int64(300),
+					ResourceRecords: []types.ResourceRecord{{Value: aws.String("192.0.2.1")}},
+					SetIdentifier:   aws.String("blue"),
+					Weight:          aws.Int64(10),
+				},
+			},
+		},
+		{
+			name: "failover record",
+			ep:   Endpoint{DNSName: "test.example.com.", Class: "A", Rdata: []string{"192.0.2.1"}, Ttl: 60, Id: "primary", Failover: "PRIMARY", HealthCheckId: "hc-1"},
+			want: types.Change{
+				Action: types.ChangeActionUpsert,
+				ResourceRecordSet: &types.ResourceRecordSet{
+					Name:            aws.String("test.example.com."),
+					Type:            types.RRTypeA,
+					TTL:             aws.Int64(60),
+					ResourceRecords: []types.ResourceRecord{{Value: aws.String("192.0.2.1")}},
+					SetIdentifier:   aws.String("primary"),
+					Failover:        types.ResourceRecordSetFailoverPrimary,
+					HealthCheckId:   aws.String("hc-1"),
+				},
+			},
+		},
+		{
+			name: "latency record",
+			ep:   Endpoint{DNSName: "test.example.com.", Class: "A", Rdata: []string{"192.0.2.1"}, Ttl: 60, Id: "tokyo", Region: "ap-northeast-1"},
+			want: types.Change{
+				Action: types.ChangeActionUpsert,
+				ResourceRecordSet: &types.ResourceRecordSet{
+					Name:            aws.String("test.example.com."),
+					Type:            types.RRTypeA,
+					TTL:             aws.Int64(60),
+					ResourceRecords: []types.ResourceRecord{{Value: aws.String("192.0.2.1")}},
+					SetIdentifier:   aws.String("tokyo"),
+					Region:          types.ResourceRecordSetRegionApNortheast1,
+				},
+			},
+		},
+		{
+			name: "geolocation record",
+			ep:   Endpoint{DNSName: "test.example.com.", Class: "A", Rdata: []string{"192.0.2.1"}, Ttl: 60, Id: "eu", GeoLocation: GeoOpts{ContinentCode: "EU"}},
+			want: types.Change{
+				Action: types.ChangeActionUpsert,
+				ResourceRecordSet: &types.ResourceRecordSet{
+					Name:            aws.String("test.example.com."),
+					Type:            types.RRTypeA,
+					TTL:             aws.Int64(60),
+					ResourceRecords: []types.ResourceRecord{{Value: aws.String("192.0.2.1")}},
+					SetIdentifier:   aws.String("eu"),
+					GeoLocation:     &types.GeoLocation{ContinentCode: aws.String("EU")},
+				},
+			},
+		},
+		{
+			name: "geoproximity record",
+			ep:   Endpoint{DNSName: "test.example.com.", Class: "A", Rdata: []string{"192.0.2.1"}, Ttl: 60, Id: "tokyo", GeoProximity: GeoProximityOpts{AWSRegion: "ap-northeast-1", Bias: 25}},
+			want: types.Change{
+				Action: types.ChangeActionUpsert,
+				ResourceRecordSet: &types.ResourceRecordSet{
+					Name:                 aws.String("test.example.com."),
+					Type:                 types.RRTypeA,
+					TTL:                  aws.Int64(60),
+					ResourceRecords:      []types.ResourceRecord{{Value: aws.String("192.0.2.1")}},
+					SetIdentifier:        aws.String("tokyo"),
+					GeoProximityLocation: &types.GeoProximityLocation{AWSRegion: aws.String("ap-northeast-1"), Bias: aws.Int32(25)},
+				},
+			},
+		},
+		{
+			name: "multivalue answer record",
+			ep:   Endpoint{DNSName: "test.example.com.", Class: "A", Rdata: []string{"192.0.2.1"}, Ttl: 60, Id: "web-1", MultiValueAnswer: true, HealthCheckId: "hc-1"},
+			want: types.Change{
+				Action: types.ChangeActionUpsert,
+				ResourceRecordSet: &types.ResourceRecordSet{
+					Name:             aws.String("test.example.com."),
+					Type:             types.RRTypeA,
+					TTL:              aws.Int64(60),
+					ResourceRecords:  []types.ResourceRecord{{Value: aws.String("192.0.2.1")}},
+					SetIdentifier:    aws.String("web-1"),
+					HealthCheckId:    aws.String("hc-1"),
+					MultiValueAnswer: aws.Bool(true),
+				},
+			},
+		},
+		{
+			name: "IP-based record",
+			ep:   Endpoint{DNSName: "test.example.com.", Class: "A", Rdata: []string{"192.0.2.1"}, Ttl: 60, Id: "tokyo", CidrRouting: CidrOpts{CollectionId: "c-1", LocationName: "tokyo"}},
+			want: types.Change{
+				Action: types.ChangeActionUpsert,
+				ResourceRecordSet: &types.ResourceRecordSet{
+					Name:              aws.String("test.example.com."),
+					Type:              types.RRTypeA,
+					TTL:               aws.Int64(60),
+					ResourceRecords:   []types.ResourceRecord{{Value: aws.String("192.0.2.1")}},
+					SetIdentifier:     aws.String("tokyo"),
+					CidrRoutingConfig: &types.CidrRoutingConfig{CollectionId: aws.String("c-1"), LocationName: aws.String("tokyo")},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newChange(types.ChangeActionUpsert, tt.ep)
+			if diff := cmp.Diff(got, tt.want, cmpopts.IgnoreUnexported(types.Change{}, types.ResourceRecordSet{}, types.ResourceRecord{}, types.GeoLocation{}, types.GeoProximityLocation{}, types.CidrRoutingConfig{})); diff != "" {
+				t.Errorf("differs: (-got +want)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewEndpointFromRecordSet(t *testing.T) {
+	tests := []struct {
+		name string
+		r    types.ResourceRecordSet
+		want Endpoint
+	}{
+		{
+			name: "failover record",
+			r: types.ResourceRecordSet{
+				Name:            aws.String("test.example.com."),
+				Type:            types.RRTypeA,
+				TTL:             aws.Int64(60),
+				ResourceRecords: []types.ResourceRecord{{Value: aws.String("192.0.2.2")}},
+				SetIdentifier:   aws.String("secondary"),
+				Failover:        types.ResourceRecordSetFailoverSecondary,
+			},
+			want: Endpoint{DNSName: "test.example.com.", Class: "A", Rdata: []string{"192.0.2.2"}, Ttl: 60, Id: "secondary", Failover: "SECONDARY"},
+		},
+		{
+			name: "latency record",
+			r: types.ResourceRecordSet{
+				Name:            aws.String("test.example.com."),
+				Type:            types.RRTypeA,
+				TTL:             aws.Int64(60),
+				ResourceRecords: []types.ResourceRecord{{Value: aws.String("192.0.2.1")}},
+				SetIdentifier:   aws.String("tokyo"),
+				Region:          types.ResourceRecordSetRegionApNortheast1,
+			},
+			want: Endpoint{DNSName: "test.example.com.", Class: "A", Rdata: []string{"192.0.2.1"}, Ttl: 60, Id: "tokyo", Region: "ap-northeast-1"},
+		},
+		{
+			name: "geolocation record",
+			r: types.ResourceRecordSet{
+				Name:            aws.String("test.example.com."),
+				Type:            types.RRTypeA,
+				TTL:             aws.Int64(60),
+				ResourceRecords: []types.ResourceRecord{{Value: aws.String("192.0.2.1")}},
+				SetIdentifier:   aws.String("california"),
+				GeoLocation:     &types.GeoLocation{CountryCode: aws.String("US"), SubdivisionCode: aws.String("CA")},
+			},
+			want: Endpoint{DNSName: "test.example.com.", Class: "A", Rdata: []string{"192.0.2.1"}, Ttl: 60, Id: "california", GeoLocation: GeoOpts{CountryCode: "US", SubdivisionCode: "CA"}},
+		},
+		{
+			name: "geoproximity record",
+			r: types.ResourceRecordSet{
+				Name:            aws.String("test.example.com."),
+				Type:            types.RRTypeA,
+				TTL:             aws.Int64(60),
+				ResourceRecords: []types.ResourceRecord{{Value: aws.String("192.0.2.1")}},
+				SetIdentifier:   aws.String("tokyo"),
+				GeoProximityLocation: &types.GeoProximityLocation{
+					Coordinates: &types.Coordinates{Latitude: aws.String("35.68"), Longitude: aws.String("139.76")},
+					Bias:        aws.Int32(-10),
+				},
+			},
+			want: Endpoint{DNSName: "test.example.com.", Class: "A", Rdata: []string{"192.0.2.1"}, Ttl: 60, Id: "tokyo", GeoProximity: GeoProximityOpts{Latitude: "35.68", Longitude: "139.76", Bias: -10}},
+		},
+		{
+			name: "IP-based record",
+			r: types.ResourceRecordSet{
+				Name:              aws.String("test.example.com."),
+				Type:              types.RRTypeA,
+				TTL:               aws.Int64(60),
+				ResourceRecords:   []types.ResourceRecord{{Value: aws.String("192.0.2.1")}},
+				SetIdentifier:     aws.String("default"),
+				CidrRoutingConfig: &types.CidrRoutingConfig{CollectionId: aws.String("c-1"), LocationName: aws.String("*")},
+			},
+			want: Endpoint{DNSName: "test.example.com.", Class: "A", Rdata: []string{"192.0.2.1"}, Ttl: 60, Id: "default", CidrRouting: CidrOpts{CollectionId: "c-1", LocationName: "*"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newEndpointFromRecordSet("test.example.com.", tt.r)
+			if diff := cmp.Diff(got, tt.want, cmp.AllowUnexported(Endpoint{}, AliasOpts{}, GeoOpts{}, GeoProximityOpts{}, CidrOpts{})); diff != "" {
+				t.Errorf("differs: (-got +want)\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dnsv1alpha1 "github.com/ch1aki/dns-rr/api/v1alpha1"
+)
+
+// NewVPCOwner returns the backend of the account which owns a VPC in another
+// account. It is built from the credentials of the VPC only and is not bound
+// to a hosted zone. Secrets without a namespace are read from namespace.
+func NewVPCOwner(ctx context.Context, namespace string, vpc dnsv1alpha1.VPC, c client.Client) (VPCAssociationProvider, error) {
+	if vpc.Auth == nil {
+		return nil, &TerminalError{Err: fmt.Errorf("vpc %s has no credentials", vpc.ID)}
+	}
+
+	optFns := []func(*config.LoadOptions) error{config.WithRegion(vpc.Region)}
+	if vpc.Auth.SecretRef != nil {
+		cred, _, err := credFromSecretRef(ctx, namespace, vpc.Auth.SecretRef, c)
+		if err != nil {
+			return nil, err
+		}
+		optFns = append(optFns, config.WithCredentialsProvider(cred))
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, optFns...)
+	if err != nil {
+		return nil, fmt.Errorf("load config error: %w", err)
+	}
+
+	// assume role option, chained from the credentials above
+	if assumeRole := vpc.Auth.AssumeRole; assumeRole != nil {
+		cfg.Credentials = aws.NewCredentialsCache(newAssumeRoleProvider(cfg, assumeRole, roleSessionName(assumeRole, namespace, vpc.ID)))
+	}
+
+	return &Route53Provider{client: route53.NewFromConfig(cfg)}, nil
+}
+
+// ZoneVPCs returns the VPCs the hosted zone is associated with.
+func (p Route53Provider) ZoneVPCs(ctx context.Context, zoneId string) ([]dnsv1alpha1.VPC, error) {
+	output, err := p.client.GetHostedZone(ctx, &route53.GetHostedZoneInput{Id: aws.String(zoneId)})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get hosted zone %s", zoneId)
+	}
+	vpcs := make([]dnsv1alpha1.VPC, len(output.VPCs))
+	for i, v := range output.VPCs {
+		vpcs[i] = dnsv1alpha1.VPC{ID: aws.ToString(v.VPCId), Region: string(v.VPCRegion)}
+	}
+	return vpcs, nil
+}
+
+// AssociateVPC associates the VPC with the hosted zone.
+func (p Route53Provider) AssociateVPC(ctx context.Context, zoneId string, vpc dnsv1alpha1.VPC) error {
+	_, err := p.client.AssociateVPCWithHostedZone(ctx, &route53.AssociateVPCWithHostedZoneInput{
+		HostedZoneId: aws.String(zoneId),
+		VPC:          route53VPC(vpc),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to associate vpc %s with hosted zone %s", vpc.ID, zoneId)
+	}
+	return nil
+}
+
+// DisassociateVPC disassociates the VPC from the hosted zone. A VPC which is
+// not associated any more is skipped.
+func (p Route53Provider) DisassociateVPC(ctx context.Context, zoneId string, vpc dnsv1alpha1.VPC) error {
+	_, err := p.client.DisassociateVPCFromHostedZone(ctx, &route53.DisassociateVPCFromHostedZoneInput{
+		HostedZoneId: aws.String(zoneId),
+		VPC:          route53VPC(vpc),
+	})
+	var nf *types.VPCAssociationNotFound
+	if err != nil && !errors.As(err, &nf) {
+		return errors.Wrapf(err, "failed to disassociate vpc %s from hosted zone %s", vpc.ID, zoneId)
+	}
+	return nil
+}
+
+// AuthorizeVPCAssociation allows the account of the VPC to associate it with the hosted zone.
+func (p Route53Provider) AuthorizeVPCAssociation(ctx context.Context, zoneId string, vpc dnsv1alpha1.VPC) error {
+	_, err := p.client.CreateVPCAssociationAuthorization(ctx, &route53.CreateVPCAssociationAuthorizationInput{
+		HostedZoneId: aws.String(zoneId),
+		VPC:          route53VPC(vpc),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to authorize association of vpc %s with hosted zone %s", vpc.ID, zoneId)
+	}
+	return nil
+}
+
+// RevokeVPCAssociationAuthorization removes the authorization. An
+// authorization which does not exist is skipped.
+func (p Route53Provider) RevokeVPCAssociationAuthorization(ctx context.Context, zoneId string, vpc dnsv1alpha1.VPC) error {
+	_, err := p.client.DeleteVPCAssociationAuthorization(ctx, &route53.DeleteVPCAssociationAuthorizationInput{
+		HostedZoneId: aws.String(zoneId),
+		VPC:          route53VPC(vpc),
+	})
+	var nf *types.VPCAssociationAuthorizationNotFound
+	if err != nil && !errors.As(err, &nf) {
+		return errors.Wrapf(err, "failed to revoke association authorization of vpc %s with hosted zone %s", vpc.ID, zoneId)
+	}
+	return nil
+}
+
+func route53VPC(vpc dnsv1alpha1.VPC) *types.VPC {
+	return &types.VPC{
+		VPCId:     aws.String(vpc.ID),
+		VPCRegion: types.VPCRegion(vpc.Region),
+	}
+}
@@ -0,0 +1,157 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	// changeBatchWindow is how long changes are collected before they are submitted.
+	changeBatchWindow = 200 * time.Millisecond
+	// changeBatchTimeout bounds the submission of a batch, which does not
+	// belong to any single reconcile.
+	changeBatchTimeout = 30 * time.Second
+
+	// maxBatchChanges is the Route53 limit of changes in a batch. UPSERTs count twice.
+	maxBatchChanges = 1000
+	// maxBatchChars is the Route53 limit of characters in the values of a batch.
+	maxBatchChars = 32000
+)
+
+// route53Batcher coalesces the changes of concurrent reconciles per Provider
+// identity and hosted zone, so that a batch is only submitted with the
+// credentials of the reconciles it contains.
+var route53Batcher = newChangeBatcher(changeBatchWindow)
+
+// submitFunc submits changes as one change batch and returns its ID.
+type submitFunc func(ctx context.Context, changes []Change) (string, error)
+
+// changeBatcher collects the changes submitted for a key during a short window
+// and submits them together, so that many reconciles of one zone do not
+// exceed the API rate limit.
+type changeBatcher struct {
+	window time.Duration
+
+	mu      sync.Mutex
+	batches map[string]*pendingBatch
+}
+
+type pendingBatch struct {
+	submit   submitFunc
+	requests []*batchRequest
+	size     int
+	chars    int
+}
+
+type batchRequest struct {
+	changes []Change
+	done    chan batchResult
+}
+
+type batchResult struct {
+	changeId string
+	err      error
+}
+
+func newChangeBatcher(window time.Duration) *changeBatcher {
+	return &changeBatcher{
+		window:  window,
+		batches: make(map[string]*pendingBatch),
+	}
+}
+
+// Submit adds changes to the pending batch of key and waits for the batch to
+// be submitted. submit is used when the changes start a new batch, so the
+// key has to identify the client submit uses.
+// It returns the ID of the batch which contains the changes.
+func (b *changeBatcher) Submit(ctx context.Context, key string, changes []Change, submit submitFunc) (string, error) {
+	req := &batchRequest{changes: changes, done: make(chan batchResult, 1)}
+	size, chars := batchSize(changes)
+	if maxBatchChanges < size || maxBatchChars < chars {
+		// the changes of a request are applied atomically, so they are not split
+		return "", &TerminalError{Err: fmt.Errorf("%d changes with %d characters exceed the limit of %d changes and %d characters of a change batch, split the names of the owner", size, chars, maxBatchChanges, maxBatchChars)}
+	}
+
+	b.mu.Lock()
+	pb := b.batches[key]
+	if pb != nil && (maxBatchChanges < pb.size+size || maxBatchChars < pb.chars+chars) {
+		// the batch is full, submit it now
+		delete(b.batches, key)
+		go b.flush(pb)
+		pb = nil
+	}
+	if pb == nil {
+		pb = &pendingBatch{submit: submit}
+		b.batches[key] = pb
+		time.AfterFunc(b.window, func() {
+			b.mu.Lock()
+			if b.batches[key] != pb {
+				// already submitted because it was full
+				b.mu.Unlock()
+				return
+			}
+			delete(b.batches, key)
+			b.mu.Unlock()
+			b.flush(pb)
+		})
+	}
+	pb.requests = append(pb.requests, req)
+	pb.size += size
+	pb.chars += chars
+	b.mu.Unlock()
+
+	select {
+	case res := <-req.done:
+		return res.changeId, res.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// flush submits the batch. When a batch of several requests is rejected as
+// invalid, the requests are submitted one by one so that each gets its own
+// result. Other errors, e.g. throttling, are returned to every request.
+func (b *changeBatcher) flush(pb *pendingBatch) {
+	ctx, cancel := context.WithTimeout(context.Background(), changeBatchTimeout)
+	defer cancel()
+
+	var changes []Change
+	for _, req := range pb.requests {
+		changes = append(changes, req.changes...)
+	}
+	changeId, err := pb.submit(ctx, changes)
+	var invalid *types.InvalidChangeBatch
+	if !errors.As(err, &invalid) || len(pb.requests) == 1 {
+		for _, req := range pb.requests {
+			req.done <- batchResult{changeId: changeId, err: err}
+		}
+		return
+	}
+
+	for _, req := range pb.requests {
+		changeId, err := pb.submit(ctx, req.changes)
+		req.done <- batchResult{changeId: changeId, err: err}
+	}
+}
+
+// batchSize returns the number of changes and value characters as counted
+// against the Route53 batch limits.
+func batchSize(changes []Change) (int, int) {
+	var size, chars int
+	for _, c := range changes {
+		n := 1
+		if c.Action == ChangeActionUpsert {
+			n = 2
+		}
+		size += n
+		for _, v := range c.Endpoint.Rdata {
+			chars += n * len(v)
+		}
+	}
+	return size, chars
+}
@@ -0,0 +1,166 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/aws-sdk-go-v2/service/route53/types"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// recordingSubmitter records the submitted batches, rejects batches which
+// contain a change for a name in reject and fails every batch with err.
+type recordingSubmitter struct {
+	mu      sync.Mutex
+	batches [][]Change
+	reject  string
+	err     error
+}
+
+func (s *recordingSubmitter) submit(ctx context.Context, changes []Change) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batches = append(s.batches, changes)
+	if s.err != nil {
+		return "", s.err
+	}
+	for _, c := range changes {
+		if c.Endpoint.DNSName == s.reject {
+			return "", &types.InvalidChangeBatch{Message: aws.String(fmt.Sprintf("invalid change for %s", c.Endpoint.DNSName))}
+		}
+	}
+	return fmt.Sprintf("C%d", len(s.batches)), nil
+}
+
+func TestChangeBatcher(t *testing.T) {
+	newChanges := func(name string, n int, value string) []Change {
+		changes := make([]Change, n)
+		for i := range changes {
+			changes[i] = Change{Action: ChangeActionCreate, Endpoint: Endpoint{DNSName: fmt.Sprintf("%s-%d.example.com.", name, i), Class: "TXT", Rdata: []string{value}}}
+		}
+		return changes
+	}
+
+	tests := []struct {
+		name        string
+		requests    [][]Change
+		reject      string
+		err         error
+		wantBatches int
+		wantErrs    []bool
+	}{
+		{
+			name:        "coalesce concurrent requests",
+			requests:    [][]Change{newChanges("a", 1, `"a"`), newChanges("b", 2, `"b"`), newChanges("c", 3, `"c"`)},
+			wantBatches: 1,
+			wantErrs:    []bool{false, false, false},
+		},
+		{
+			name:        "split at the change limit",
+			requests:    [][]Change{newChanges("a", 600, `"a"`), newChanges("b", 600, `"b"`)},
+			wantBatches: 2,
+			wantErrs:    []bool{false, false},
+		},
+		{
+			name:        "split at the character limit",
+			requests:    [][]Change{newChanges("a", 1, strings.Repeat("a", 20000)), newChanges("b", 1, strings.Repeat("b", 20000))},
+			wantBatches: 2,
+			wantErrs:    []bool{false, false},
+		},
+		{
+			name:        "retry requests one by one on an invalid batch",
+			requests:    [][]Change{newChanges("a", 1, `"a"`), newChanges("b", 1, `"b"`)},
+			reject:      "b-0.example.com.",
+			wantBatches: 3,
+			wantErrs:    []bool{false, true},
+		},
+		{
+			name:        "return other errors to every request",
+			requests:    [][]Change{newChanges("a", 1, `"a"`), newChanges("b", 1, `"b"`)},
+			err:         &types.ThrottlingException{Message: aws.String("Rate exceeded")},
+			wantBatches: 1,
+			wantErrs:    []bool{true, true},
+		},
+		{
+			name:        "reject a request over the limits",
+			requests:    [][]Change{newChanges("a", 1001, `"a"`)},
+			wantBatches: 0,
+			wantErrs:    []bool{true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newChangeBatcher(50 * time.Millisecond)
+			s := &recordingSubmitter{reject: tt.reject, err: tt.err}
+
+			errs := make([]error, len(tt.requests))
+			var wg sync.WaitGroup
+			for i, changes := range tt.requests {
+				wg.Add(1)
+				go func(i int, changes []Change) {
+					defer wg.Done()
+					_, errs[i] = b.Submit(context.TODO(), "Z0123456789ABCDEFGHIJ", changes, s.submit)
+				}(i, changes)
+			}
+			wg.Wait()
+
+			if len(s.batches) != tt.wantBatches {
+				t.Errorf("expected %d batches, got %d", tt.wantBatches, len(s.batches))
+			}
+			for i, err := range errs {
+				if (err != nil) != tt.wantErrs[i] {
+					t.Errorf("request %d: error = %v, wantErr %v", i, err, tt.wantErrs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestApplyChangesSeparatesProviders(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	// the providers share the zone but not their credentials
+	providers := make([]Route53Provider, 2)
+	for i := range providers {
+		r53api := NewMockRoute53API(controller)
+		r53api.EXPECT().ChangeResourceRecordSets(gomock.Any(), gomock.Any()).
+			Return(&route53.ChangeResourceRecordSetsOutput{ChangeInfo: &types.ChangeInfo{}}, nil).Times(1)
+		providers[i] = Route53Provider{client: r53api, hostedZoneId: "Z0123456789ABCDEFGHIJ", identity: fmt.Sprintf("provider-%d", i)}
+	}
+
+	var wg sync.WaitGroup
+	for i, p := range providers {
+		wg.Add(1)
+		go func(i int, p Route53Provider) {
+			defer wg.Done()
+			changes := []Change{{Action: ChangeActionCreate, Endpoint: Endpoint{DNSName: fmt.Sprintf("%d.example.com.", i), Class: "A", Rdata: []string{"192.0.2.1"}}}}
+			if _, err := p.ApplyChanges(context.TODO(), changes); err != nil {
+				t.Errorf("ApplyChanges() error = %v", err)
+			}
+		}(i, p)
+	}
+	wg.Wait()
+}
+
+func TestBatchSize(t *testing.T) {
+	changes := []Change{
+		{Action: ChangeActionCreate, Endpoint: Endpoint{Rdata: []string{"192.0.2.1"}}},
+		{Action: ChangeActionUpsert, Endpoint: Endpoint{Rdata: []string{"192.0.2.1", "192.0.2.2"}}},
+		{Action: ChangeActionDelete, Endpoint: Endpoint{IsAlias: true}},
+	}
+	size, chars := batchSize(changes)
+	if size != 4 {
+		t.Errorf("expected size 4, got %d", size)
+	}
+	if chars != 45 {
+		t.Errorf("expected 45 characters, got %d", chars)
+	}
+}
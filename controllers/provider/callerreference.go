@@ -0,0 +1,20 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// newCallerReference returns a caller reference which is unique to each
+// creation, so that a resource deleted outside of the controller can be
+// created again. It starts with the owner, which finds a created resource
+// again when its ID was not recorded.
+var newCallerReference = func(owner string) string {
+	return fmt.Sprintf("%s-%d", owner, time.Now().UnixNano())
+}
+
+// ownsCallerReference reports whether the caller reference was made for the owner.
+func ownsCallerReference(callerReference, owner string) bool {
+	return strings.HasPrefix(callerReference, owner+"-")
+}
@@ -0,0 +1,223 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"sort"
+)
+
+// maxCidrChanges is the Route53 limit of CIDR blocks changed by one request.
+const maxCidrChanges = 1000
+
+// CidrCollectionProvider is implemented by backends which route queries by
+// the CIDR blocks of the client, grouped by location in collections.
+type CidrCollectionProvider interface {
+	// CidrCollection returns the collection with the name, or nil if it does not exist.
+	CidrCollection(ctx context.Context, name string) (*CidrCollection, error)
+
+	// CreateCidrCollection creates an empty collection, or returns the
+	// collection created before with the same callerReference. A collection of
+	// the name created with another callerReference is a TerminalError
+	// wrapping ErrCidrCollectionNotOwned.
+	CreateCidrCollection(ctx context.Context, name, callerReference string) (*CidrCollection, error)
+
+	// CidrBlocks returns the CIDR blocks of the collection keyed by location.
+	CidrBlocks(ctx context.Context, id string) (map[string][]string, error)
+
+	// ChangeCidrCollection applies the changes when the collection still has the version.
+	ChangeCidrCollection(ctx context.Context, id string, version int64, changes []cidrChange) error
+
+	// DeleteCidrCollection deletes the empty collection.
+	DeleteCidrCollection(ctx context.Context, id string) error
+}
+
+// CidrCollection describes a collection of CIDR blocks.
+type CidrCollection struct {
+	ID      string
+	Name    string
+	Version int64
+}
+
+type cidrChangeAction string
+
+const (
+	cidrChangeActionPut    cidrChangeAction = "PUT"
+	cidrChangeActionDelete cidrChangeAction = "DELETE_IF_EXISTS"
+)
+
+type cidrChange struct {
+	action   cidrChangeAction
+	location string
+	cidrs    []string
+}
+
+// AsCidrCollectionProvider returns p as a CidrCollectionProvider, or a
+// TerminalError if the backend does not support CIDR collections.
+func AsCidrCollectionProvider(p DNSProvider) (CidrCollectionProvider, error) {
+	cp, ok := p.(CidrCollectionProvider)
+	if !ok {
+		return nil, &TerminalError{Err: fmt.Errorf("provider does not support CIDR collections")}
+	}
+	return cp, nil
+}
+
+// ConvergeCidrCollection creates the collection for the owner when it does
+// not exist and makes its CIDR blocks equal to locations. A collection of the
+// name created by another owner is never changed. It returns the collection
+// after the change.
+func ConvergeCidrCollection(ctx context.Context, p CidrCollectionProvider, name, owner string, locations map[string][]string) (*CidrCollection, error) {
+	desired, err := canonicalLocations(locations)
+	if err != nil {
+		return nil, err
+	}
+
+	// the owner is the caller reference, so this claims the collection
+	if _, err := p.CreateCidrCollection(ctx, name, owner); err != nil {
+		return nil, err
+	}
+	return changeCidrBlocks(ctx, p, name, desired)
+}
+
+// DeleteCidrCollection removes the CIDR blocks of the collection with the
+// name and deletes it. A collection which does not exist is skipped, and one
+// created by another owner is a TerminalError wrapping ErrCidrCollectionNotOwned.
+func DeleteCidrCollection(ctx context.Context, p CidrCollectionProvider, name, owner string) error {
+	collection, err := p.CidrCollection(ctx, name)
+	if err != nil || collection == nil {
+		return err
+	}
+	if _, err := p.CreateCidrCollection(ctx, name, owner); err != nil {
+		return err
+	}
+
+	collection, err = changeCidrBlocks(ctx, p, name, nil)
+	if err != nil {
+		return err
+	}
+	return p.DeleteCidrCollection(ctx, collection.ID)
+}
+
+// changeCidrBlocks makes the CIDR blocks of the collection equal to desired,
+// in as many requests as the limit of the backend requires.
+func changeCidrBlocks(ctx context.Context, p CidrCollectionProvider, name string, desired map[string][]string) (*CidrCollection, error) {
+	collection, err := p.CidrCollection(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if collection == nil {
+		return nil, fmt.Errorf("cidr collection %s not found", name)
+	}
+
+	live, err := p.CidrBlocks(ctx, collection.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, changes := range splitCidrChanges(cidrChanges(desired, live), maxCidrChanges) {
+		if err := p.ChangeCidrCollection(ctx, collection.ID, collection.Version, changes); err != nil {
+			return nil, err
+		}
+		// the version is incremented by every change
+		collection, err = p.CidrCollection(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if collection == nil {
+			return nil, fmt.Errorf("cidr collection %s not found", name)
+		}
+	}
+	return collection, nil
+}
+
+// canonicalLocations validates the CIDR blocks and masks them, e.g.
+// 192.0.2.1/24 to 192.0.2.0/24, as the backend stores them.
+func canonicalLocations(locations map[string][]string) (map[string][]string, error) {
+	canonical := make(map[string][]string, len(locations))
+	for location, cidrs := range locations {
+		for _, cidr := range cidrs {
+			prefix, err := netip.ParsePrefix(cidr)
+			if err != nil {
+				return nil, &TerminalError{Err: fmt.Errorf("invalid cidr block %q of location %s: %w", cidr, location, err)}
+			}
+			canonical[location] = append(canonical[location], prefix.Masked().String())
+		}
+	}
+	return canonical, nil
+}
+
+// splitCidrChanges splits the changes into requests of at most max CIDR blocks.
+func splitCidrChanges(changes []cidrChange, max int) [][]cidrChange {
+	var requests [][]cidrChange
+	var request []cidrChange
+	n := 0
+	for _, c := range changes {
+		for cidrs := c.cidrs; 0 < len(cidrs); {
+			if n == max {
+				requests = append(requests, request)
+				request, n = nil, 0
+			}
+			k := min(len(cidrs), max-n)
+			request = append(request, cidrChange{action: c.action, location: c.location, cidrs: cidrs[:k]})
+			cidrs = cidrs[k:]
+			n += k
+		}
+	}
+	if 0 < len(request) {
+		requests = append(requests, request)
+	}
+	return requests
+}
+
+// cidrChanges builds the changes which turn the live CIDR blocks into the
+// desired ones. A location is removed with its last CIDR block. The deletions
+// come first, so that a CIDR block can move to another location.
+func cidrChanges(desired, live map[string][]string) []cidrChange {
+	deletes := make([]cidrChange, 0)
+	puts := make([]cidrChange, 0)
+	for _, location := range sortedKeys(desired, live) {
+		want := toSet(desired[location])
+		have := toSet(live[location])
+
+		var put, del []string
+		for cidr := range want {
+			if !have[cidr] {
+				put = append(put, cidr)
+			}
+		}
+		for cidr := range have {
+			if !want[cidr] {
+				del = append(del, cidr)
+			}
+		}
+		if 0 < len(put) {
+			puts = append(puts, cidrChange{action: cidrChangeActionPut, location: location, cidrs: sortedValues(put)})
+		}
+		if 0 < len(del) {
+			deletes = append(deletes, cidrChange{action: cidrChangeActionDelete, location: location, cidrs: sortedValues(del)})
+		}
+	}
+	return append(deletes, puts...)
+}
+
+func sortedKeys(maps ...map[string][]string) []string {
+	seen := map[string]bool{}
+	keys := make([]string, 0)
+	for _, m := range maps {
+		for k := range m {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
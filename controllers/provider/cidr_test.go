@@ -0,0 +1,175 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func TestCidrChanges(t *testing.T) {
+	tests := []struct {
+		name    string
+		desired map[string][]string
+		live    map[string][]string
+		want    []cidrChange
+	}{
+		{
+			name:    "in sync",
+			desired: map[string][]string{"tokyo": {"192.0.2.0/24"}},
+			live:    map[string][]string{"tokyo": {"192.0.2.0/24"}},
+			want:    []cidrChange{},
+		},
+		{
+			name:    "add location",
+			desired: map[string][]string{"osaka": {"198.51.100.0/24"}, "tokyo": {"192.0.2.0/24"}},
+			live:    map[string][]string{"tokyo": {"192.0.2.0/24"}},
+			want:    []cidrChange{{action: cidrChangeActionPut, location: "osaka", cidrs: []string{"198.51.100.0/24"}}},
+		},
+		{
+			name:    "replace blocks",
+			desired: map[string][]string{"tokyo": {"192.0.2.0/24", "203.0.113.0/24"}},
+			live:    map[string][]string{"tokyo": {"192.0.2.0/24", "198.51.100.0/24"}},
+			want: []cidrChange{
+				{action: cidrChangeActionDelete, location: "tokyo", cidrs: []string{"198.51.100.0/24"}},
+				{action: cidrChangeActionPut, location: "tokyo", cidrs: []string{"203.0.113.0/24"}},
+			},
+		},
+		{
+			name:    "move block to another location",
+			desired: map[string][]string{"osaka": {"192.0.2.0/24"}},
+			live:    map[string][]string{"tokyo": {"192.0.2.0/24"}},
+			want: []cidrChange{
+				{action: cidrChangeActionDelete, location: "tokyo", cidrs: []string{"192.0.2.0/24"}},
+				{action: cidrChangeActionPut, location: "osaka", cidrs: []string{"192.0.2.0/24"}},
+			},
+		},
+		{
+			name: "remove all",
+			live: map[string][]string{"tokyo": {"192.0.2.0/24"}},
+			want: []cidrChange{{action: cidrChangeActionDelete, location: "tokyo", cidrs: []string{"192.0.2.0/24"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := cidrChanges(tt.desired, tt.live)
+			if diff := cmp.Diff(got, tt.want, cmp.AllowUnexported(cidrChange{})); diff != "" {
+				t.Errorf("differs: (-got +want)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConvergeCidrCollection(t *testing.T) {
+	collection := types.CollectionSummary{Id: aws.String("c-1"), Name: aws.String("offices"), Version: aws.Int64(1)}
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	r53api := NewMockRoute53API(controller)
+	gomock.InOrder(
+		r53api.EXPECT().CreateCidrCollection(context.TODO(), &route53.CreateCidrCollectionInput{Name: aws.String("offices"), CallerReference: aws.String("uid")}).
+			Return(&route53.CreateCidrCollectionOutput{Collection: &types.CidrCollection{Id: collection.Id, Name: collection.Name, Version: collection.Version}}, nil),
+		r53api.EXPECT().ListCidrCollections(context.TODO(), &route53.ListCidrCollectionsInput{}).
+			Return(&route53.ListCidrCollectionsOutput{CidrCollections: []types.CollectionSummary{collection}}, nil),
+		r53api.EXPECT().ListCidrBlocks(context.TODO(), &route53.ListCidrBlocksInput{CollectionId: aws.String("c-1")}).
+			Return(&route53.ListCidrBlocksOutput{}, nil),
+		r53api.EXPECT().ChangeCidrCollection(context.TODO(), &route53.ChangeCidrCollectionInput{
+			Id:                aws.String("c-1"),
+			CollectionVersion: aws.Int64(1),
+			Changes: []types.CidrCollectionChange{
+				{Action: types.CidrCollectionChangeActionPut, LocationName: aws.String("tokyo"), CidrList: []string{"192.0.2.0/24"}},
+			},
+		}).Return(&route53.ChangeCidrCollectionOutput{}, nil),
+		r53api.EXPECT().ListCidrCollections(context.TODO(), &route53.ListCidrCollectionsInput{}).
+			Return(&route53.ListCidrCollectionsOutput{CidrCollections: []types.CollectionSummary{
+				{Id: aws.String("c-1"), Name: aws.String("offices"), Version: aws.Int64(2)},
+			}}, nil),
+	)
+	p := Route53Provider{client: r53api, hostedZoneId: "Z0123456789ABCDEFGHIJ"}
+
+	// the host bits are masked as Route53 stores the block
+	got, err := ConvergeCidrCollection(context.TODO(), p, "offices", "uid", map[string][]string{"tokyo": {"192.0.2.1/24"}})
+	if err != nil {
+		t.Fatalf("ConvergeCidrCollection() error = %v", err)
+	}
+	want := &CidrCollection{ID: "c-1", Name: "offices", Version: 2}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("differs: (-got +want)\n%s", diff)
+	}
+}
+
+func TestConvergeCidrCollectionNotOwned(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	r53api := NewMockRoute53API(controller)
+	r53api.EXPECT().CreateCidrCollection(context.TODO(), &route53.CreateCidrCollectionInput{Name: aws.String("offices"), CallerReference: aws.String("uid")}).
+		Return(nil, &types.CidrCollectionAlreadyExistsException{Message: aws.String("already exists")})
+	p := Route53Provider{client: r53api, hostedZoneId: "Z0123456789ABCDEFGHIJ"}
+
+	_, err := ConvergeCidrCollection(context.TODO(), p, "offices", "uid", map[string][]string{"tokyo": {"192.0.2.0/24"}})
+	if !errors.Is(err, ErrCidrCollectionNotOwned) || !IsTerminal(err) {
+		t.Errorf("ConvergeCidrCollection() error = %v, want terminal ErrCidrCollectionNotOwned", err)
+	}
+}
+
+func TestCanonicalLocations(t *testing.T) {
+	tests := []struct {
+		name      string
+		locations map[string][]string
+		want      map[string][]string
+		wantErr   bool
+	}{
+		{
+			name:      "mask host bits",
+			locations: map[string][]string{"tokyo": {"192.0.2.1/24", "2001:db8::1/48"}},
+			want:      map[string][]string{"tokyo": {"192.0.2.0/24", "2001:db8::/48"}},
+		},
+		{
+			name:      "invalid block",
+			locations: map[string][]string{"tokyo": {"192.0.2.0"}},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := canonicalLocations(tt.locations)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("canonicalLocations() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("differs: (-got +want)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSplitCidrChanges(t *testing.T) {
+	cidrs := func(n int) []string {
+		values := make([]string, n)
+		for i := range values {
+			values[i] = fmt.Sprintf("10.%d.%d.0/24", i/256, i%256)
+		}
+		return values
+	}
+	changes := []cidrChange{
+		{action: cidrChangeActionDelete, location: "osaka", cidrs: cidrs(3)},
+		{action: cidrChangeActionPut, location: "tokyo", cidrs: cidrs(5)},
+	}
+	got := splitCidrChanges(changes, 4)
+	want := [][]cidrChange{
+		{
+			{action: cidrChangeActionDelete, location: "osaka", cidrs: cidrs(3)},
+			{action: cidrChangeActionPut, location: "tokyo", cidrs: cidrs(5)[:1]},
+		},
+		{{action: cidrChangeActionPut, location: "tokyo", cidrs: cidrs(5)[1:5]}},
+	}
+	if diff := cmp.Diff(got, want, cmp.AllowUnexported(cidrChange{})); diff != "" {
+		t.Errorf("differs: (-got +want)\n%s", diff)
+	}
+}
@@ -0,0 +1,417 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	dnsv1alpha1 "github.com/ch1aki/dns-rr/api/v1alpha1"
+)
+
+// DNSProvider is a DNS backend which manages the records of one zone.
+type DNSProvider interface {
+	// ZoneName returns the name of the zone the records are written to.
+	ZoneName() string
+
+	// Zone looks the zone up on the backend and validates the configuration.
+	Zone(ctx context.Context) (*Zone, error)
+
+	// Records returns the live records of class for the names in the zone
+	// and the TXT records which mark their owners, both keyed by name. Only
+	// the records with the set identifier id are returned, or the records
+	// without one when id is nil.
+	Records(ctx context.Context, names []string, class string, id *string) (records map[string]Endpoint, ownerRecords map[string]Endpoint, err error)
+
+	// ApplyChanges submits the changes as one change set and returns its ID.
+	ApplyChanges(ctx context.Context, changes []Change) (string, error)
+
+	// ChangeInSync reports whether the change with the ID has propagated to
+	// all name servers of the zone.
+	ChangeInSync(ctx context.Context, changeId string) (bool, error)
+
+	// Capabilities returns the optional record features supported by the backend.
+	Capabilities() Capabilities
+}
+
+// Capabilities describes the optional record features a backend supports.
+type Capabilities struct {
+	// Alias is true when the backend supports alias records.
+	Alias bool
+
+	// Weighted is true when the backend supports weighted records.
+	Weighted bool
+
+	// Failover is true when the backend supports failover records.
+	Failover bool
+
+	// Latency is true when the backend supports latency records.
+	Latency bool
+
+	// Geolocation is true when the backend supports geolocation records.
+	Geolocation bool
+
+	// GeoProximity is true when the backend supports geoproximity records.
+	GeoProximity bool
+
+	// MultiValueAnswer is true when the backend supports multivalue answer records.
+	MultiValueAnswer bool
+
+	// CidrRouting is true when the backend supports IP-based records.
+	CidrRouting bool
+}
+
+// Refs holds the backend IDs of the objects referenced by a ResourceRecord,
+// which are resolved by the controller.
+type Refs struct {
+	// CidrCollectionID is the ID of the collection referenced by the CIDR routing.
+	CidrCollectionID string
+
+	// HealthCheckID is the ID of the health check referenced by the record.
+	HealthCheckID string
+}
+
+// Zone describes the zone managed by a provider.
+type Zone struct {
+	ID      string
+	Name    string
+	Private bool
+}
+
+// HasName reports whether the zone name equals name, ignoring case and the trailing dot.
+func (z Zone) HasName(name string) bool {
+	return strings.EqualFold(strings.TrimSuffix(z.Name, "."), strings.TrimSuffix(name, "."))
+}
+
+// ChangeAction is what a Change does to its record.
+type ChangeAction string
+
+const (
+	ChangeActionCreate ChangeAction = "CREATE"
+	ChangeActionUpsert ChangeAction = "UPSERT"
+	ChangeActionDelete ChangeAction = "DELETE"
+)
+
+// Change is a change of one record in a change set.
+type Change struct {
+	Action   ChangeAction
+	Endpoint Endpoint
+}
+
+// Endpoint is a record as it is compared with the live records of a backend.
+// Backends read their records into endpoints which compare equal to the
+// desired endpoint when nothing has to be changed.
+type Endpoint struct {
+	// The Dns Name
+	DNSName string
+	// The type of DNS Record
+	Class string
+
+	// The values of DNS Record, sorted to compare regardless of order
+	Rdata []string
+
+	// The TTL of DNS Record
+	Ttl int64
+
+	// The Id of DNS Record
+	Id string
+
+	// The Record weighte
+	Weight *int64
+
+	// The failover role of DNS Record, PRIMARY or SECONDARY
+	Failover string
+
+	// The health check associated with DNS Record
+	HealthCheckId string
+
+	// The region of latency DNS Record
+	Region string
+
+	// The location of geolocation DNS Record
+	GeoLocation GeoOpts
+
+	// The location and bias of geoproximity DNS Record
+	GeoProximity GeoProximityOpts
+
+	// The flag of multivalue answer DNS Record
+	MultiValueAnswer bool
+
+	// The collection and location of IP-based DNS Record
+	CidrRouting CidrOpts
+
+	// The flag of Alias Record
+	IsAlias bool
+
+	// The alias target of DNS Record
+	AliasTarget AliasOpts
+}
+
+// Routed reports whether the record has a routing policy, which requires the
+// id to tell the records of the same name and class apart.
+func (ep Endpoint) Routed() bool {
+	return ep.Weight != nil || ep.Failover != "" || ep.Region != "" || ep.GeoLocation != GeoOpts{} || ep.GeoProximity != GeoProximityOpts{} || ep.MultiValueAnswer || ep.CidrRouting != CidrOpts{}
+}
+
+// GeoOpts is the location of a geolocation record.
+type GeoOpts struct {
+	ContinentCode   string
+	CountryCode     string
+	SubdivisionCode string
+}
+
+// GeoProximityOpts is the location and bias of a geoproximity record.
+type GeoProximityOpts struct {
+	AWSRegion string
+	Latitude  string
+	Longitude string
+	Bias      int32
+}
+
+// CidrOpts is the collection and location of an IP-based record.
+type CidrOpts struct {
+	CollectionId string
+	LocationName string
+}
+
+// AliasOpts is the target of an alias record.
+type AliasOpts struct {
+	DNSName              string
+	HostedZoneId         string
+	EvaluateTargetHealth bool
+}
+
+// Converge makes the records of rrSpec exist for every owner and deletes the
+// records of previously managed FQDNs which are no longer listed in owners.
+// Records are only changed when they are owned by ownerId, which is recorded
+// in a companion TXT record. Existing records without an owner are claimed
+// only when adopt is set. Records owned by others are reported in a
+// ConflictError after the other records have been converged.
+// It returns the FQDNs managed after the change and the ID of the submitted
+// change, which is empty when nothing had to be changed.
+// refs holds the resolved IDs of the objects referenced by rrSpec.
+func Converge(ctx context.Context, p DNSProvider, ownerId string, owners []string, rrSpec dnsv1alpha1.ResourceRecordSpec, refs Refs, managed []string, adopt bool) ([]string, string, error) {
+	if err := checkCapabilities(p.Capabilities(), rrSpec); err != nil {
+		return nil, "", err
+	}
+
+	zoneName := p.ZoneName()
+	desired := newEndpoint(rrSpec, refs)
+
+	// names which were managed before but removed from owners
+	stale := staleOwners(owners, zoneName, managed)
+
+	// get actual endpoints
+	names := append(append([]string{}, owners...), stale...)
+	state, err := fetchLiveState(ctx, p, names, rrSpec.Class, rrSpec.SetIdentifier())
+	if err != nil {
+		return nil, "", err
+	}
+	owners, conflicts := ownedNames(owners, zoneName, ownerId, state, managed, adopt)
+	stale, staleConflicts := ownedNames(stale, zoneName, ownerId, state, managed, false)
+	conflicts = append(conflicts, staleConflicts...)
+
+	// evalute differences
+	changes := diff(owners, zoneName, desired, state.records)
+	changes = append(changes, ownerChanges(owners, zoneName, rrSpec.Class, desired.Id, ownerId, state)...)
+	changes = append(changes, deleteChanges(stale, state.records)...)
+	changes = append(changes, deleteChanges(stale, state.ownerRecords)...)
+
+	// converge
+	var changeId string
+	if 0 < len(changes) {
+		changeId, err = p.ApplyChanges(ctx, changes)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	fqdns := make([]string, len(owners))
+	for i, owner := range owners {
+		fqdns[i] = buildFQDN(owner, zoneName)
+	}
+	if 0 < len(conflicts) {
+		return fqdns, changeId, &ConflictError{FQDNs: conflicts}
+	}
+	return fqdns, changeId, nil
+}
+
+// Delete removes the records of rrSpec owned by ownerId for every owner and
+// every previously managed FQDN from the zone. Records which do not exist any
+// more or are owned by others are skipped.
+func Delete(ctx context.Context, p DNSProvider, ownerId string, owners []string, rrSpec dnsv1alpha1.ResourceRecordSpec, managed []string) error {
+	zoneName := p.ZoneName()
+	names := append(append([]string{}, owners...), staleOwners(owners, zoneName, managed)...)
+
+	// get actual endpoints
+	state, err := fetchLiveState(ctx, p, names, rrSpec.Class, rrSpec.SetIdentifier())
+	if err != nil {
+		return err
+	}
+	names, _ = ownedNames(names, zoneName, ownerId, state, managed, false)
+
+	changes := deleteChanges(names, state.records)
+	changes = append(changes, deleteChanges(names, state.ownerRecords)...)
+	if len(changes) == 0 {
+		return nil
+	}
+	_, err = p.ApplyChanges(ctx, changes)
+	return err
+}
+
+// checkCapabilities rejects record features which the backend can not express.
+func checkCapabilities(caps Capabilities, rrSpec dnsv1alpha1.ResourceRecordSpec) error {
+	switch {
+	case rrSpec.IsAlias && !caps.Alias:
+		return &TerminalError{Err: fmt.Errorf("provider does not support alias records")}
+	case rrSpec.Weight != nil && !caps.Weighted:
+		return &TerminalError{Err: fmt.Errorf("provider does not support weighted records")}
+	case rrSpec.Failover != nil && !caps.Failover:
+		return &TerminalError{Err: fmt.Errorf("provider does not support failover records")}
+	case rrSpec.Region != "" && !caps.Latency:
+		return &TerminalError{Err: fmt.Errorf("provider does not support latency records")}
+	case rrSpec.GeoLocation != nil && !caps.Geolocation:
+		return &TerminalError{Err: fmt.Errorf("provider does not support geolocation records")}
+	case rrSpec.GeoProximity != nil && !caps.GeoProximity:
+		return &TerminalError{Err: fmt.Errorf("provider does not support geoproximity records")}
+	case rrSpec.MultiValueAnswer != nil && !caps.MultiValueAnswer:
+		return &TerminalError{Err: fmt.Errorf("provider does not support multivalue answer records")}
+	case rrSpec.CidrRouting != nil && !caps.CidrRouting:
+		return &TerminalError{Err: fmt.Errorf("provider does not support IP-based records")}
+	}
+	return nil
+}
+
+// newEndpoint builds the desired endpoint of rrSpec. dnsName is filled per owner.
+func newEndpoint(rrSpec dnsv1alpha1.ResourceRecordSpec, refs Refs) Endpoint {
+	desired := Endpoint{
+		Class: rrSpec.Class,
+	}
+	if rrSpec.IsAlias {
+		desired.IsAlias = true
+		desired.AliasTarget = AliasOpts{
+			DNSName:              rrSpec.AliasTarget.Record,
+			HostedZoneId:         rrSpec.AliasTarget.HostedZoneID,
+			EvaluateTargetHealth: rrSpec.AliasTarget.EvaluateTargetHealth,
+		}
+	} else {
+		desired.Rdata = sortedValues(rrSpec.Values())
+		if rrSpec.Ttl != nil {
+			desired.Ttl = int64(*rrSpec.Ttl)
+		}
+	}
+
+	if rrSpec.Weight != nil {
+		desired.Weight = rrSpec.Weight
+	}
+	if rrSpec.Failover != nil {
+		desired.Failover = string(rrSpec.Failover.Type)
+		desired.HealthCheckId = rrSpec.Failover.HealthCheckID
+	}
+	if rrSpec.MultiValueAnswer != nil {
+		desired.MultiValueAnswer = true
+		desired.HealthCheckId = rrSpec.MultiValueAnswer.HealthCheckID
+	}
+	desired.Region = rrSpec.Region
+	if rrSpec.GeoLocation != nil {
+		desired.GeoLocation = GeoOpts{
+			ContinentCode:   rrSpec.GeoLocation.ContinentCode,
+			CountryCode:     rrSpec.GeoLocation.CountryCode,
+			SubdivisionCode: rrSpec.GeoLocation.SubdivisionCode,
+		}
+	}
+	if gp := rrSpec.GeoProximity; gp != nil {
+		desired.GeoProximity.AWSRegion = gp.AWSRegion
+		if gp.Coordinates != nil {
+			desired.GeoProximity.Latitude = gp.Coordinates.Latitude
+			desired.GeoProximity.Longitude = gp.Coordinates.Longitude
+		}
+		if gp.Bias != nil {
+			desired.GeoProximity.Bias = *gp.Bias
+		}
+	}
+	if refs.HealthCheckID != "" {
+		desired.HealthCheckId = refs.HealthCheckID
+	}
+	if rrSpec.CidrRouting != nil {
+		desired.CidrRouting = CidrOpts{
+			CollectionId: refs.CidrCollectionID,
+			LocationName: rrSpec.CidrRouting.LocationName,
+		}
+	}
+	if id := rrSpec.SetIdentifier(); id != nil {
+		desired.Id = *id
+	}
+	return desired
+}
+
+func diff(owners []string, zoneName string, desiredEp Endpoint, actualEps map[string]Endpoint) []Change {
+	changes := make([]Change, 0)
+
+	for _, owner := range owners {
+		// build changes
+		desiredEp.DNSName = buildFQDN(owner, zoneName)
+
+		// evaluate difference
+		if _, exist := actualEps[owner]; !exist {
+			// レコードが存在しなかった場合
+			changes = append(changes, Change{Action: ChangeActionCreate, Endpoint: desiredEp})
+		} else if actualEps[owner].Region != desiredEp.Region {
+			// the region is part of the identity of a latency record, so it is replaced
+			changes = append(changes, Change{Action: ChangeActionDelete, Endpoint: actualEps[owner]})
+			changes = append(changes, Change{Action: ChangeActionCreate, Endpoint: desiredEp})
+		} else if !reflect.DeepEqual(desiredEp, actualEps[owner]) {
+			// 値が異なる場合
+			changes = append(changes, Change{Action: ChangeActionUpsert, Endpoint: desiredEp})
+		}
+	}
+	return changes
+}
+
+// deleteChanges builds DELETE changes for every live record of the owners.
+// Backends like Route53 require the deleted record to match the current one
+// exactly, so the changes are built from the actual endpoints.
+func deleteChanges(owners []string, actualEps map[string]Endpoint) []Change {
+	changes := make([]Change, 0)
+	for _, owner := range owners {
+		if ep, exist := actualEps[owner]; exist {
+			changes = append(changes, Change{Action: ChangeActionDelete, Endpoint: ep})
+		}
+	}
+	return changes
+}
+
+// staleOwners returns the owner names of the managed FQDNs in the zone that
+// are not listed in owners any more.
+func staleOwners(owners []string, zoneName string, managed []string) []string {
+	current := make(map[string]bool, len(owners))
+	for _, owner := range owners {
+		current[buildFQDN(owner, zoneName)] = true
+	}
+
+	suffix := "." + strings.TrimSuffix(zoneName, ".") + "."
+	stale := make([]string, 0)
+	for _, fqdn := range managed {
+		if current[fqdn] || !strings.HasSuffix(fqdn, suffix) {
+			continue
+		}
+		stale = append(stale, strings.TrimSuffix(fqdn, suffix))
+	}
+	return stale
+}
+
+// sortedValues returns a sorted copy of values.
+func sortedValues(values []string) []string {
+	sorted := append([]string{}, values...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+func buildFQDN(owner, zone string) string {
+	fqdn := fmt.Sprintf("%s.%s", owner, zone)
+	if !strings.HasSuffix(fqdn, ".") {
+		fqdn = fqdn + "."
+	}
+	return fqdn
+}
@@ -0,0 +1,278 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/google/go-cmp/cmp"
+
+	dnsv1alpha1 "github.com/ch1aki/dns-rr/api/v1alpha1"
+)
+
+// fakeProvider is an in-memory DNSProvider
+type fakeProvider struct {
+	zoneName string
+	caps     Capabilities
+	records  map[string]Endpoint
+	applied  []Change
+}
+
+func (f *fakeProvider) ZoneName() string { return f.zoneName }
+
+func (f *fakeProvider) Zone(ctx context.Context) (*Zone, error) {
+	return &Zone{Name: f.zoneName}, nil
+}
+
+func (f *fakeProvider) Records(ctx context.Context, names []string, class string, id *string) (map[string]Endpoint, map[string]Endpoint, error) {
+	var sid string
+	if id != nil {
+		sid = *id
+	}
+	eps := map[string]Endpoint{}
+	owners := map[string]Endpoint{}
+	for _, name := range names {
+		if ep, exist := f.records[name]; exist && ep.Class == class {
+			eps[name] = ep
+		}
+		if ep, exist := f.records[ownerRecordName(name, class, sid)]; exist && ep.Class == "TXT" {
+			owners[name] = ep
+		}
+	}
+	return eps, owners, nil
+}
+
+func (f *fakeProvider) ApplyChanges(ctx context.Context, changes []Change) (string, error) {
+	f.applied = append(f.applied, changes...)
+	return "change-id", nil
+}
+
+func (f *fakeProvider) ChangeInSync(ctx context.Context, changeId string) (bool, error) {
+	return true, nil
+}
+
+func (f *fakeProvider) Capabilities() Capabilities { return f.caps }
+
+func TestConverge(t *testing.T) {
+	ownerTxt := func(name, value string) Endpoint {
+		return Endpoint{DNSName: name, Class: "TXT", Rdata: []string{value}, Ttl: 300}
+	}
+	tests := []struct {
+		name         string
+		provider     *fakeProvider
+		owners       []string
+		rrSpec       dnsv1alpha1.ResourceRecordSpec
+		refs         Refs
+		managed      []string
+		adopt        bool
+		wantFQDNs    []string
+		wantChangeId string
+		wantApplied  []Change
+		wantErr      bool
+	}{
+		{
+			name: "in sync",
+			provider: &fakeProvider{zoneName: "example.com", records: map[string]Endpoint{
+				"test":           {DNSName: "test.example.com.", Class: "A", Rdata: []string{"192.0.2.1"}, Ttl: 300},
+				"_dns-rr-a.test": ownerTxt("_dns-rr-a.test.example.com.", `"dns-rr-owner: default/ns/rr"`),
+			}},
+			owners:    []string{"test"},
+			rrSpec:    dnsv1alpha1.ResourceRecordSpec{Class: "A", Ttl: aws.Int32(300), Rdata: "192.0.2.1"},
+			managed:   []string{"test.example.com."},
+			wantFQDNs: []string{"test.example.com."},
+		},
+		{
+			name: "write owner record of managed record",
+			provider: &fakeProvider{zoneName: "example.com", records: map[string]Endpoint{
+				"test": {DNSName: "test.example.com.", Class: "A", Rdata: []string{"192.0.2.1"}, Ttl: 300},
+			}},
+			owners:       []string{"test"},
+			rrSpec:       dnsv1alpha1.ResourceRecordSpec{Class: "A", Ttl: aws.Int32(300), Rdata: "192.0.2.1"},
+			managed:      []string{"test.example.com."},
+			wantFQDNs:    []string{"test.example.com."},
+			wantChangeId: "change-id",
+			wantApplied: []Change{
+				{Action: ChangeActionCreate, Endpoint: ownerTxt("_dns-rr-a.test.example.com.", `"dns-rr-owner: default/ns/rr"`)},
+			},
+		},
+		{
+			name: "create and prune",
+			provider: &fakeProvider{zoneName: "example.com", records: map[string]Endpoint{
+				"old":           {DNSName: "old.example.com.", Class: "A", Rdata: []string{"192.0.2.1"}, Ttl: 300},
+				"_dns-rr-a.old": ownerTxt("_dns-rr-a.old.example.com.", `"dns-rr-owner: default/ns/rr"`),
+			}},
+			owners:       []string{"test"},
+			rrSpec:       dnsv1alpha1.ResourceRecordSpec{Class: "A", Ttl: aws.Int32(300), Rdata: "192.0.2.1"},
+			managed:      []string{"old.example.com."},
+			wantFQDNs:    []string{"test.example.com."},
+			wantChangeId: "change-id",
+			wantApplied: []Change{
+				{Action: ChangeActionCreate, Endpoint: Endpoint{DNSName: "test.example.com.", Class: "A", Rdata: []string{"192.0.2.1"}, Ttl: 300}},
+				{Action: ChangeActionCreate, Endpoint: ownerTxt("_dns-rr-a.test.example.com.", `"dns-rr-owner: default/ns/rr"`)},
+				{Action: ChangeActionDelete, Endpoint: Endpoint{DNSName: "old.example.com.", Class: "A", Rdata: []string{"192.0.2.1"}, Ttl: 300}},
+				{Action: ChangeActionDelete, Endpoint: ownerTxt("_dns-rr-a.old.example.com.", `"dns-rr-owner: default/ns/rr"`)},
+			},
+		},
+		{
+			name: "skip records owned by others",
+			provider: &fakeProvider{zoneName: "example.com", records: map[string]Endpoint{
+				"manual":          {DNSName: "manual.example.com.", Class: "A", Rdata: []string{"198.51.100.1"}, Ttl: 300},
+				"other":           {DNSName: "other.example.com.", Class: "A", Rdata: []string{"198.51.100.1"}, Ttl: 300},
+				"_dns-rr-a.other": ownerTxt("_dns-rr-a.other.example.com.", `"dns-rr-owner: default/ns/other"`),
+			}},
+			owners:       []string{"manual", "other", "test"},
+			rrSpec:       dnsv1alpha1.ResourceRecordSpec{Class: "A", Ttl: aws.Int32(300), Rdata: "192.0.2.1"},
+			wantFQDNs:    []string{"test.example.com."},
+			wantChangeId: "change-id",
+			wantApplied: []Change{
+				{Action: ChangeActionCreate, Endpoint: Endpoint{DNSName: "test.example.com.", Class: "A", Rdata: []string{"192.0.2.1"}, Ttl: 300}},
+				{Action: ChangeActionCreate, Endpoint: ownerTxt("_dns-rr-a.test.example.com.", `"dns-rr-owner: default/ns/rr"`)},
+			},
+			wantErr: true,
+		},
+		{
+			name: "adopt unowned records",
+			provider: &fakeProvider{zoneName: "example.com", records: map[string]Endpoint{
+				"manual":          {DNSName: "manual.example.com.", Class: "A", Rdata: []string{"198.51.100.1"}, Ttl: 300},
+				"other":           {DNSName: "other.example.com.", Class: "A", Rdata: []string{"198.51.100.1"}, Ttl: 300},
+				"_dns-rr-a.other": ownerTxt("_dns-rr-a.other.example.com.", `"dns-rr-owner: default/ns/other"`),
+			}},
+			owners:       []string{"manual", "other"},
+			rrSpec:       dnsv1alpha1.ResourceRecordSpec{Class: "A", Ttl: aws.Int32(300), Rdata: "192.0.2.1"},
+			adopt:        true,
+			wantFQDNs:    []string{"manual.example.com."},
+			wantChangeId: "change-id",
+			wantApplied: []Change{
+				{Action: ChangeActionUpsert, Endpoint: Endpoint{DNSName: "manual.example.com.", Class: "A", Rdata: []string{"192.0.2.1"}, Ttl: 300}},
+				{Action: ChangeActionCreate, Endpoint: ownerTxt("_dns-rr-a.manual.example.com.", `"dns-rr-owner: default/ns/rr"`)},
+			},
+			wantErr: true,
+		},
+		{
+			name:     "unsupported weighted record",
+			provider: &fakeProvider{zoneName: "example.com", caps: Capabilities{Alias: true}},
+			owners:   []string{"test"},
+			rrSpec:   dnsv1alpha1.ResourceRecordSpec{Class: "A", Ttl: aws.Int32(300), Rdata: "192.0.2.1", Weight: aws.Int64(10), Id: aws.String("test")},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fqdns, changeId, err := Converge(context.TODO(), tt.provider, "default/ns/rr", tt.owners, tt.rrSpec, tt.refs, tt.managed, tt.adopt)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Converge() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(fqdns, tt.wantFQDNs); diff != "" {
+				t.Errorf("fqdns differs: (-got +want)\n%s", diff)
+			}
+			if changeId != tt.wantChangeId {
+				t.Errorf("Converge() changeId = %v, want %v", changeId, tt.wantChangeId)
+			}
+			opts := cmp.AllowUnexported(Change{}, Endpoint{}, AliasOpts{}, GeoOpts{}, GeoProximityOpts{}, CidrOpts{})
+			if diff := cmp.Diff(tt.provider.applied, tt.wantApplied, opts); diff != "" {
+				t.Errorf("applied changes differs: (-got +want)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	p := &fakeProvider{zoneName: "example.com", records: map[string]Endpoint{
+		"test":            {DNSName: "test.example.com.", Class: "A", Rdata: []string{"192.0.2.1"}, Ttl: 300},
+		"_dns-rr-a.test":  {DNSName: "_dns-rr-a.test.example.com.", Class: "TXT", Rdata: []string{`"dns-rr-owner: default/ns/rr"`}, Ttl: 300},
+		"other":           {DNSName: "other.example.com.", Class: "A", Rdata: []string{"198.51.100.1"}, Ttl: 300},
+		"_dns-rr-a.other": {DNSName: "_dns-rr-a.other.example.com.", Class: "TXT", Rdata: []string{`"dns-rr-owner: default/ns/other"`}, Ttl: 300},
+	}}
+	err := Delete(context.TODO(), p, "default/ns/rr", []string{"test", "other"}, dnsv1alpha1.ResourceRecordSpec{Class: "A"}, nil)
+	if err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	want := []Change{
+		{Action: ChangeActionDelete, Endpoint: p.records["test"]},
+		{Action: ChangeActionDelete, Endpoint: p.records["_dns-rr-a.test"]},
+	}
+	if diff := cmp.Diff(p.applied, want, cmp.AllowUnexported(Change{}, Endpoint{}, AliasOpts{}, GeoOpts{}, GeoProximityOpts{}, CidrOpts{})); diff != "" {
+		t.Errorf("applied changes differs: (-got +want)\n%s", diff)
+	}
+}
+
+func TestNewEndpoint(t *testing.T) {
+	tests := []struct {
+		name   string
+		rrSpec dnsv1alpha1.ResourceRecordSpec
+		refs   Refs
+		want   Endpoint
+	}{
+		{
+			name:   "single value",
+			rrSpec: dnsv1alpha1.ResourceRecordSpec{Class: "A", Ttl: aws.Int32(300), Rdata: "192.0.2.1"},
+			want:   Endpoint{Class: "A", Ttl: 300, Rdata: []string{"192.0.2.1"}},
+		},
+		{
+			name:   "multi value sorted",
+			rrSpec: dnsv1alpha1.ResourceRecordSpec{Class: "A", Ttl: aws.Int32(300), Rdatas: []string{"192.0.2.2", "192.0.2.1"}},
+			want:   Endpoint{Class: "A", Ttl: 300, Rdata: []string{"192.0.2.1", "192.0.2.2"}},
+		},
+		{
+			name: "failover",
+			rrSpec: dnsv1alpha1.ResourceRecordSpec{
+				Class: "A", Ttl: aws.Int32(60), Rdata: "192.0.2.1", Id: aws.String("primary"),
+				Failover: &dnsv1alpha1.Failover{Type: dnsv1alpha1.FailoverPrimary, HealthCheckID: "hc-1"},
+			},
+			want: Endpoint{Class: "A", Ttl: 60, Rdata: []string{"192.0.2.1"}, Id: "primary", Failover: "PRIMARY", HealthCheckId: "hc-1"},
+		},
+		{
+			name: "health check ref",
+			rrSpec: dnsv1alpha1.ResourceRecordSpec{
+				Class: "A", Ttl: aws.Int32(60), Rdata: "192.0.2.1", Id: aws.String("primary"),
+				Failover: &dnsv1alpha1.Failover{Type: dnsv1alpha1.FailoverPrimary}, HealthCheckRef: "web",
+			},
+			refs: Refs{HealthCheckID: "abcdef11-2222-3333-4444-555555555555"},
+			want: Endpoint{Class: "A", Ttl: 60, Rdata: []string{"192.0.2.1"}, Id: "primary", Failover: "PRIMARY", HealthCheckId: "abcdef11-2222-3333-4444-555555555555"},
+		},
+		{
+			name: "cidr routing",
+			rrSpec: dnsv1alpha1.ResourceRecordSpec{
+				Class: "A", Ttl: aws.Int32(60), Rdata: "192.0.2.1", Id: aws.String("office"),
+				CidrRouting: &dnsv1alpha1.CidrRouting{CollectionRef: "offices", LocationName: "tokyo"},
+			},
+			refs: Refs{CidrCollectionID: "c8c8c8c8-1111-2222-3333-444444444444"},
+			want: Endpoint{Class: "A", Ttl: 60, Rdata: []string{"192.0.2.1"}, Id: "office", CidrRouting: CidrOpts{CollectionId: "c8c8c8c8-1111-2222-3333-444444444444", LocationName: "tokyo"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newEndpoint(tt.rrSpec, tt.refs)
+			if diff := cmp.Diff(got, tt.want, cmp.AllowUnexported(Endpoint{}, AliasOpts{}, GeoOpts{}, GeoProximityOpts{}, CidrOpts{})); diff != "" {
+				t.Errorf("differs: (-got +want)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestOwnerRecordName(t *testing.T) {
+	long := strings.Repeat("a", 60)
+	tests := []struct {
+		name string
+		id   string
+		want string
+	}{
+		{name: "no id", want: "_dns-rr-a.www"},
+		{name: "id", id: "primary", want: "_dns-rr-a-primary.www"},
+		{name: "long id", id: long, want: "_dns-rr-a-11ee391211c6256460b6ed375957fadd.www"},
+		{name: "id with capitals", id: "Primary", want: "_dns-rr-a-efe10c80ec8a9f38624cbb47103010a8.www"},
+		{name: "id with a space", id: "blue green", want: "_dns-rr-a-a8036affb32fc5b1c4ddebd055b5cd47.www"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ownerRecordName("www", "A", tt.id)
+			if got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+			if label := strings.SplitN(got, ".", 2)[0]; maxLabelLength < len(label) {
+				t.Errorf("label %s is longer than %d", label, maxLabelLength)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/aws/smithy-go"
+	"github.com/pkg/errors"
+)
+
+// authErrorCodes are the API error codes returned for rejected credentials.
+var authErrorCodes = map[string]bool{
+	"AccessDenied":                true,
+	"AccessDeniedException":       true,
+	"ExpiredToken":                true,
+	"InvalidClientTokenId":        true,
+	"SignatureDoesNotMatch":       true,
+	"UnrecognizedClientException": true,
+}
+
+// ErrZoneMismatch is returned when the zone found by its ID has another name than configured.
+var ErrZoneMismatch = errors.New("zone name mismatch")
+
+// ErrCidrCollectionNotOwned is returned when a CIDR collection of the name was created by another owner.
+var ErrCidrCollectionNotOwned = errors.New("cidr collection not owned")
+
+// ErrZoneNotFound is returned when no hosted zone is found by the configured name.
+var ErrZoneNotFound = errors.New("zone not found")
+
+// ErrZoneAmbiguous is returned when several hosted zones are found by the configured name.
+var ErrZoneAmbiguous = errors.New("zone name ambiguous")
+
+// TerminalError is an error which is not resolved by retrying the same request,
+// e.g. a misconfiguration or a change batch rejected by the provider.
+type TerminalError struct {
+	Err error
+}
+
+func (e *TerminalError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *TerminalError) Unwrap() error {
+	return e.Err
+}
+
+// IsTerminal reports whether err should not be retried until the resource changes.
+func IsTerminal(err error) bool {
+	var (
+		te   *TerminalError
+		icb  *types.InvalidChangeBatch
+		ii   *types.InvalidInput
+		nshz *types.NoSuchHostedZone
+		nsc  *types.NoSuchChange
+	)
+	switch {
+	case errors.As(err, &te):
+	case errors.As(err, &icb):
+	case errors.As(err, &ii):
+	case errors.As(err, &nshz):
+	case errors.As(err, &nsc):
+	default:
+		return false
+	}
+	return true
+}
+
+// IsAuthError reports whether err was caused by credentials rejected by the provider.
+func IsAuthError(err error) bool {
+	var ae smithy.APIError
+	return errors.As(err, &ae) && authErrorCodes[ae.ErrorCode()]
+}
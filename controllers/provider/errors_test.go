@@ -0,0 +1,98 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/aws/smithy-go"
+	"github.com/pkg/errors"
+)
+
+func TestIsTerminal(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "terminal error",
+			err:  &TerminalError{Err: fmt.Errorf("route53 provider require region")},
+			want: true,
+		},
+		{
+			name: "wrapped invalid change batch",
+			err:  errors.Wrapf(&types.InvalidChangeBatch{Message: aws.String("invalid")}, "failes to change resource records set for zone %s", "Z0123456789ABCDEFGHIJ"),
+			want: true,
+		},
+		{
+			name: "no such hosted zone",
+			err:  &types.NoSuchHostedZone{Message: aws.String("not found")},
+			want: true,
+		},
+		{
+			name: "no such change",
+			err:  &types.NoSuchChange{Message: aws.String("not found")},
+			want: true,
+		},
+		{
+			name: "health check caller reference reused",
+			err:  &types.HealthCheckAlreadyExists{Message: aws.String("already exists")},
+			want: false,
+		},
+		{
+			name: "health check in use",
+			err:  &types.HealthCheckInUse{Message: aws.String("in use")},
+			want: false,
+		},
+		{
+			name: "throttling",
+			err:  &types.ThrottlingException{Message: aws.String("rate exceeded")},
+			want: false,
+		},
+		{
+			name: "other error",
+			err:  fmt.Errorf("connection refused"),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTerminal(tt.err); got != tt.want {
+				t.Errorf("IsTerminal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsAuthError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "invalid token",
+			err:  errors.Wrapf(&smithy.GenericAPIError{Code: "InvalidClientTokenId"}, "failed to get hosted zone %s", "Z0123456789ABCDEFGHIJ"),
+			want: true,
+		},
+		{
+			name: "no such hosted zone",
+			err:  &types.NoSuchHostedZone{Message: aws.String("not found")},
+			want: false,
+		},
+		{
+			name: "other error",
+			err:  fmt.Errorf("connection refused"),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsAuthError(tt.err); got != tt.want {
+				t.Errorf("IsAuthError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	dnsv1alpha1 "github.com/ch1aki/dns-rr/api/v1alpha1"
+)
+
+// HealthCheckProvider is implemented by backends which check the health of
+// the endpoints records point to.
+type HealthCheckProvider interface {
+	// HealthCheck returns the health check with the ID, or nil if it does not exist.
+	HealthCheck(ctx context.Context, id string) (*HealthCheck, error)
+
+	// OwnedHealthCheck returns the health check created with a caller
+	// reference of the owner, or nil if there is none.
+	OwnedHealthCheck(ctx context.Context, owner string) (*HealthCheck, error)
+
+	// CreateHealthCheck creates a health check. The backend rejects a
+	// callerReference which was already used, even when the health check was
+	// deleted since.
+	CreateHealthCheck(ctx context.Context, callerReference string, config healthCheckConfig) (*HealthCheck, error)
+
+	// UpdateHealthCheck changes the health check when it still has the version of current.
+	UpdateHealthCheck(ctx context.Context, current *HealthCheck, config healthCheckConfig) (*HealthCheck, error)
+
+	// DeleteHealthCheck deletes the health check. A health check which does not exist is skipped.
+	DeleteHealthCheck(ctx context.Context, id string) error
+
+	// HealthStatus returns the latest status reported by the checkers.
+	HealthStatus(ctx context.Context, id string) (dnsv1alpha1.HealthStatus, error)
+}
+
+// HealthCheck describes a health check of the backend.
+type HealthCheck struct {
+	ID      string
+	Version int64
+
+	config healthCheckConfig
+}
+
+// healthCheckConfig is the configuration of a health check with the defaults
+// of its type applied, so that it can be compared with the live one.
+type healthCheckConfig struct {
+	hcType string
+
+	ipAddress        string
+	fqdn             string
+	port             int32
+	resourcePath     string
+	searchString     string
+	requestInterval  int32
+	failureThreshold int32
+	measureLatency   bool
+	enableSNI        bool
+	regions          []string
+
+	childHealthChecks []string
+	healthThreshold   int32
+
+	alarmName                    string
+	alarmRegion                  string
+	insufficientDataHealthStatus string
+
+	inverted bool
+	disabled bool
+}
+
+// AsHealthCheckProvider returns p as a HealthCheckProvider, or a
+// TerminalError if the backend does not support health checks.
+func AsHealthCheckProvider(p DNSProvider) (HealthCheckProvider, error) {
+	hp, ok := p.(HealthCheckProvider)
+	if !ok {
+		return nil, &TerminalError{Err: fmt.Errorf("provider does not support health checks")}
+	}
+	return hp, nil
+}
+
+// ConvergeHealthCheck creates the health check when id is empty or does not
+// exist any more, and updates it when it differs from spec. A health check
+// created for the owner whose id was not recorded is adopted instead of
+// created again. childIds are the IDs of the children of a CALCULATED check.
+// It returns the health check after the change.
+func ConvergeHealthCheck(ctx context.Context, p HealthCheckProvider, id, owner string, spec dnsv1alpha1.HealthCheckSpec, childIds []string) (*HealthCheck, error) {
+	desired, err := newHealthCheckConfig(spec, childIds)
+	if err != nil {
+		return nil, err
+	}
+
+	var current *HealthCheck
+	if id != "" {
+		current, err = p.HealthCheck(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if current == nil {
+		current, err = p.OwnedHealthCheck(ctx, owner)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if current == nil {
+		return p.CreateHealthCheck(ctx, newCallerReference(owner), desired)
+	}
+	id = current.ID
+
+	live := current.config
+	if live.hcType != desired.hcType || live.requestInterval != desired.requestInterval || live.measureLatency != desired.measureLatency {
+		return nil, &TerminalError{Err: fmt.Errorf("type, requestInterval and measureLatency of health check %s can not be changed", id)}
+	}
+	if reflect.DeepEqual(live, desired) {
+		return current, nil
+	}
+	return p.UpdateHealthCheck(ctx, current, desired)
+}
+
+// newHealthCheckConfig validates spec and builds its configuration. Only the
+// fields which apply to the type are kept.
+func newHealthCheckConfig(spec dnsv1alpha1.HealthCheckSpec, childIds []string) (healthCheckConfig, error) {
+	config := healthCheckConfig{
+		hcType:   string(spec.Type),
+		inverted: spec.Inverted,
+		disabled: spec.Disabled,
+	}
+
+	switch spec.Type {
+	case dnsv1alpha1.HealthCheckHTTP, dnsv1alpha1.HealthCheckHTTPS, dnsv1alpha1.HealthCheckHTTPStrMatch, dnsv1alpha1.HealthCheckHTTPSStrMatch, dnsv1alpha1.HealthCheckTCP:
+		if spec.IPAddress == "" && spec.FullyQualifiedDomainName == "" {
+			return config, &TerminalError{Err: fmt.Errorf("ipAddress or fullyQualifiedDomainName is required for %s health checks", spec.Type)}
+		}
+		isHTTPS := spec.Type == dnsv1alpha1.HealthCheckHTTPS || spec.Type == dnsv1alpha1.HealthCheckHTTPSStrMatch
+		isStrMatch := spec.Type == dnsv1alpha1.HealthCheckHTTPStrMatch || spec.Type == dnsv1alpha1.HealthCheckHTTPSStrMatch
+
+		config.ipAddress = spec.IPAddress
+		config.fqdn = spec.FullyQualifiedDomainName
+		switch {
+		case spec.Port != nil:
+			config.port = *spec.Port
+		case spec.Type == dnsv1alpha1.HealthCheckTCP:
+			return config, &TerminalError{Err: fmt.Errorf("port is required for TCP health checks")}
+		case isHTTPS:
+			config.port = 443
+		default:
+			config.port = 80
+		}
+		if spec.Type != dnsv1alpha1.HealthCheckTCP {
+			config.resourcePath = spec.ResourcePath
+		}
+		if isStrMatch {
+			if spec.SearchString == "" {
+				return config, &TerminalError{Err: fmt.Errorf("searchString is required for %s health checks", spec.Type)}
+			}
+			config.searchString = spec.SearchString
+		}
+		config.requestInterval = 30
+		if spec.RequestInterval != nil {
+			config.requestInterval = *spec.RequestInterval
+		}
+		config.failureThreshold = 3
+		if spec.FailureThreshold != nil {
+			config.failureThreshold = *spec.FailureThreshold
+		}
+		config.measureLatency = spec.MeasureLatency
+		config.enableSNI = isHTTPS
+		if spec.EnableSNI != nil {
+			config.enableSNI = *spec.EnableSNI
+		}
+		config.regions = sortedValues(spec.Regions)
+	case dnsv1alpha1.HealthCheckCalculated:
+		if len(childIds) == 0 {
+			return config, &TerminalError{Err: fmt.Errorf("childHealthChecks are required for CALCULATED health checks")}
+		}
+		config.childHealthChecks = sortedValues(childIds)
+		config.healthThreshold = int32(len(childIds))
+		if spec.HealthThreshold != nil {
+			config.healthThreshold = *spec.HealthThreshold
+		}
+	case dnsv1alpha1.HealthCheckCloudWatchMetric:
+		if spec.AlarmIdentifier == nil {
+			return config, &TerminalError{Err: fmt.Errorf("alarmIdentifier is required for CLOUDWATCH_METRIC health checks")}
+		}
+		config.alarmName = spec.AlarmIdentifier.Name
+		config.alarmRegion = spec.AlarmIdentifier.Region
+		config.insufficientDataHealthStatus = "LastKnownStatus"
+		if spec.InsufficientDataHealthStatus != "" {
+			config.insufficientDataHealthStatus = spec.InsufficientDataHealthStatus
+		}
+	default:
+		return config, &TerminalError{Err: fmt.Errorf("unknown health check type %s", spec.Type)}
+	}
+	return config, nil
+}
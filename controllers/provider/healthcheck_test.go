@@ -0,0 +1,284 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+
+	dnsv1alpha1 "github.com/ch1aki/dns-rr/api/v1alpha1"
+)
+
+func TestNewHealthCheckConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     dnsv1alpha1.HealthCheckSpec
+		childIds []string
+		want     healthCheckConfig
+		wantErr  bool
+	}{
+		{
+			name: "HTTPS defaults",
+			spec: dnsv1alpha1.HealthCheckSpec{Type: dnsv1alpha1.HealthCheckHTTPS, FullyQualifiedDomainName: "www.example.com", ResourcePath: "/healthz"},
+			want: healthCheckConfig{hcType: "HTTPS", fqdn: "www.example.com", port: 443, resourcePath: "/healthz", requestInterval: 30, failureThreshold: 3, enableSNI: true, regions: []string{}},
+		},
+		{
+			name: "TCP",
+			spec: dnsv1alpha1.HealthCheckSpec{Type: dnsv1alpha1.HealthCheckTCP, IPAddress: "192.0.2.1", Port: aws.Int32(5432), RequestInterval: aws.Int32(10), ResourcePath: "/ignored"},
+			want: healthCheckConfig{hcType: "TCP", ipAddress: "192.0.2.1", port: 5432, requestInterval: 10, failureThreshold: 3, regions: []string{}},
+		},
+		{
+			name:    "TCP without port",
+			spec:    dnsv1alpha1.HealthCheckSpec{Type: dnsv1alpha1.HealthCheckTCP, IPAddress: "192.0.2.1"},
+			wantErr: true,
+		},
+		{
+			name:    "string matching without search string",
+			spec:    dnsv1alpha1.HealthCheckSpec{Type: dnsv1alpha1.HealthCheckHTTPStrMatch, IPAddress: "192.0.2.1"},
+			wantErr: true,
+		},
+		{
+			name:    "HTTP without endpoint",
+			spec:    dnsv1alpha1.HealthCheckSpec{Type: dnsv1alpha1.HealthCheckHTTP},
+			wantErr: true,
+		},
+		{
+			name:     "calculated",
+			spec:     dnsv1alpha1.HealthCheckSpec{Type: dnsv1alpha1.HealthCheckCalculated, ChildHealthChecks: []string{"a", "b"}},
+			childIds: []string{"hc-2", "hc-1"},
+			want:     healthCheckConfig{hcType: "CALCULATED", childHealthChecks: []string{"hc-1", "hc-2"}, healthThreshold: 2},
+		},
+		{
+			name: "cloudwatch metric",
+			spec: dnsv1alpha1.HealthCheckSpec{Type: dnsv1alpha1.HealthCheckCloudWatchMetric, AlarmIdentifier: &dnsv1alpha1.AlarmIdentifier{Name: "errors", Region: "ap-northeast-1"}},
+			want: healthCheckConfig{hcType: "CLOUDWATCH_METRIC", alarmName: "errors", alarmRegion: "ap-northeast-1", insufficientDataHealthStatus: "LastKnownStatus"},
+		},
+		{
+			name:    "cloudwatch metric without alarm",
+			spec:    dnsv1alpha1.HealthCheckSpec{Type: dnsv1alpha1.HealthCheckCloudWatchMetric},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newHealthCheckConfig(tt.spec, tt.childIds)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newHealthCheckConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := cmp.Diff(got, tt.want, cmp.AllowUnexported(healthCheckConfig{})); diff != "" {
+				t.Errorf("differs: (-got +want)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConvergeHealthCheck(t *testing.T) {
+	defer func(f func(string) string) { newCallerReference = f }(newCallerReference)
+	newCallerReference = func(owner string) string { return owner + "-1" }
+
+	live := &types.HealthCheck{
+		Id:                 aws.String("hc-1"),
+		HealthCheckVersion: aws.Int64(1),
+		HealthCheckConfig: &types.HealthCheckConfig{
+			Type:                     types.HealthCheckTypeHttp,
+			FullyQualifiedDomainName: aws.String("www.example.com"),
+			Port:                     aws.Int32(80),
+			RequestInterval:          aws.Int32(30),
+			FailureThreshold:         aws.Int32(3),
+			MeasureLatency:           aws.Bool(false),
+			EnableSNI:                aws.Bool(false),
+			Inverted:                 aws.Bool(false),
+			Disabled:                 aws.Bool(false),
+			Regions:                  []types.HealthCheckRegion{types.HealthCheckRegionUsEast1, types.HealthCheckRegionEuWest1, types.HealthCheckRegionApNortheast1},
+		},
+	}
+	allRegions := *live
+	allRegions.HealthCheckConfig = &types.HealthCheckConfig{}
+	*allRegions.HealthCheckConfig = *live.HealthCheckConfig
+	allRegions.HealthCheckConfig.Regions = nil
+	spec := dnsv1alpha1.HealthCheckSpec{Type: dnsv1alpha1.HealthCheckHTTP, FullyQualifiedDomainName: "www.example.com"}
+	regionalSpec := spec
+	regionalSpec.Regions = []string{"us-east-1", "eu-west-1", "ap-northeast-1"}
+
+	owned := *live
+	owned.CallerReference = aws.String("uid-1-5")
+	other := *live
+	other.Id = aws.String("hc-2")
+	other.CallerReference = aws.String("uid-2-5")
+
+	tests := []struct {
+		name       string
+		id         string
+		spec       dnsv1alpha1.HealthCheckSpec
+		live       *types.HealthCheck
+		listed     []types.HealthCheck
+		wantCreate bool
+		wantUpdate *route53.UpdateHealthCheckInput
+		wantErr    bool
+	}{
+		{
+			name:       "create",
+			spec:       spec,
+			wantCreate: true,
+		},
+		{
+			name:       "recreate deleted",
+			id:         "hc-1",
+			spec:       spec,
+			listed:     []types.HealthCheck{other},
+			wantCreate: true,
+		},
+		{
+			name:   "adopt health check created before status was lost",
+			spec:   regionalSpec,
+			listed: []types.HealthCheck{other, owned},
+		},
+		{
+			name: "in sync",
+			id:   "hc-1",
+			spec: regionalSpec,
+			live: live,
+		},
+		{
+			name: "in sync with all regions",
+			id:   "hc-1",
+			spec: spec,
+			live: &allRegions,
+		},
+		{
+			name: "reset regions",
+			id:   "hc-1",
+			spec: spec,
+			live: live,
+			wantUpdate: &route53.UpdateHealthCheckInput{
+				HealthCheckId:            aws.String("hc-1"),
+				HealthCheckVersion:       aws.Int64(1),
+				FullyQualifiedDomainName: aws.String("www.example.com"),
+				Port:                     aws.Int32(80),
+				FailureThreshold:         aws.Int32(3),
+				EnableSNI:                aws.Bool(false),
+				Inverted:                 aws.Bool(false),
+				Disabled:                 aws.Bool(false),
+				ResetElements:            []types.ResettableElementName{types.ResettableElementNameRegions},
+			},
+		},
+		{
+			name: "update",
+			id:   "hc-1",
+			spec: dnsv1alpha1.HealthCheckSpec{Type: dnsv1alpha1.HealthCheckHTTP, IPAddress: "192.0.2.1", FailureThreshold: aws.Int32(5), Regions: regionalSpec.Regions},
+			live: live,
+			wantUpdate: &route53.UpdateHealthCheckInput{
+				HealthCheckId:      aws.String("hc-1"),
+				HealthCheckVersion: aws.Int64(1),
+				IPAddress:          aws.String("192.0.2.1"),
+				Port:               aws.Int32(80),
+				FailureThreshold:   aws.Int32(5),
+				EnableSNI:          aws.Bool(false),
+				Regions:            []types.HealthCheckRegion{types.HealthCheckRegionApNortheast1, types.HealthCheckRegionEuWest1, types.HealthCheckRegionUsEast1},
+				Inverted:           aws.Bool(false),
+				Disabled:           aws.Bool(false),
+				ResetElements:      []types.ResettableElementName{types.ResettableElementNameFullyQualifiedDomainName},
+			},
+		},
+		{
+			name:    "change type",
+			id:      "hc-1",
+			spec:    dnsv1alpha1.HealthCheckSpec{Type: dnsv1alpha1.HealthCheckHTTPS, FullyQualifiedDomainName: "www.example.com"},
+			live:    live,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+			r53api := NewMockRoute53API(controller)
+			if tt.id != "" {
+				var err error
+				if tt.live == nil {
+					err = &types.NoSuchHealthCheck{Message: aws.String("not found")}
+				}
+				r53api.EXPECT().GetHealthCheck(context.TODO(), &route53.GetHealthCheckInput{HealthCheckId: aws.String(tt.id)}).
+					Return(&route53.GetHealthCheckOutput{HealthCheck: tt.live}, err)
+			}
+			if tt.live == nil {
+				r53api.EXPECT().ListHealthChecks(context.TODO(), &route53.ListHealthChecksInput{}).
+					Return(&route53.ListHealthChecksOutput{HealthChecks: tt.listed}, nil)
+			}
+			if tt.wantCreate {
+				r53api.EXPECT().CreateHealthCheck(context.TODO(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, params *route53.CreateHealthCheckInput, _ ...func(*route53.Options)) (*route53.CreateHealthCheckOutput, error) {
+						if got := aws.ToString(params.CallerReference); got != "uid-1-1" {
+							t.Errorf("expected caller reference uid-1-1, got %s", got)
+						}
+						return &route53.CreateHealthCheckOutput{HealthCheck: live}, nil
+					})
+			}
+			if tt.wantUpdate != nil {
+				r53api.EXPECT().UpdateHealthCheck(context.TODO(), tt.wantUpdate).
+					Return(&route53.UpdateHealthCheckOutput{HealthCheck: live}, nil)
+			}
+			p := Route53Provider{client: r53api, hostedZoneId: "Z0123456789ABCDEFGHIJ"}
+
+			got, err := ConvergeHealthCheck(context.TODO(), p, tt.id, "uid-1", tt.spec, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ConvergeHealthCheck() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.ID != "hc-1" {
+				t.Errorf("expected hc-1, got %s", got.ID)
+			}
+		})
+	}
+}
+
+func TestHealthStatus(t *testing.T) {
+	observation := func(status string) types.HealthCheckObservation {
+		return types.HealthCheckObservation{StatusReport: &types.StatusReport{Status: aws.String(status)}}
+	}
+	tests := []struct {
+		name         string
+		observations []types.HealthCheckObservation
+		want         dnsv1alpha1.HealthStatus
+	}{
+		{
+			name: "no observations",
+			want: dnsv1alpha1.HealthStatusUnknown,
+		},
+		{
+			name: "healthy",
+			observations: []types.HealthCheckObservation{
+				observation("Success: HTTP Status Code 200, OK"),
+				observation("Failure: Connection timed out."),
+				observation("Failure: Connection timed out."),
+				observation("Failure: Connection timed out."),
+			},
+			want: dnsv1alpha1.HealthStatusHealthy,
+		},
+		{
+			name: "unhealthy",
+			observations: []types.HealthCheckObservation{
+				observation("Success: HTTP Status Code 200, OK"),
+				observation("Failure: Connection timed out."),
+				observation("Failure: Connection timed out."),
+				observation("Failure: Connection timed out."),
+				observation("Failure: Connection timed out."),
+				observation("Failure: Connection timed out."),
+			},
+			want: dnsv1alpha1.HealthStatusUnhealthy,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := healthStatus(tt.observations); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
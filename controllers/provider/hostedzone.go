@@ -0,0 +1,183 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	dnsv1alpha1 "github.com/ch1aki/dns-rr/api/v1alpha1"
+)
+
+// defaultDelegationTtl is the TTL of the NS records written into a parent zone.
+const defaultDelegationTtl int32 = 172800
+
+// HostedZoneProvider is implemented by backends which create and delete zones.
+type HostedZoneProvider interface {
+	// HostedZone returns the zone with the ID, or nil if it does not exist.
+	HostedZone(ctx context.Context, id string) (*HostedZone, error)
+
+	// OwnedHostedZone returns the zone of the name created with a caller
+	// reference of the owner, or nil if there is none.
+	OwnedHostedZone(ctx context.Context, name, owner string) (*HostedZone, error)
+
+	// CreateHostedZone creates a zone. The backend rejects a callerReference
+	// which was already used, even when the zone was deleted since.
+	CreateHostedZone(ctx context.Context, callerReference string, config hostedZoneConfig) (*HostedZone, error)
+
+	// UpdateHostedZoneComment changes the comment of the zone.
+	UpdateHostedZoneComment(ctx context.Context, id, comment string) error
+
+	// ChangeHostedZoneTags adds or overwrites the tags in add and removes the keys in remove.
+	ChangeHostedZoneTags(ctx context.Context, id string, add map[string]string, remove []string) error
+
+	// DeleteHostedZone deletes the zone, which must not contain records other
+	// than its SOA and NS. A zone which does not exist is skipped.
+	DeleteHostedZone(ctx context.Context, id string) error
+}
+
+// HostedZone describes a zone of the backend.
+type HostedZone struct {
+	Zone
+
+	Comment     string
+	NameServers []string
+	Tags        map[string]string
+}
+
+type hostedZoneConfig struct {
+	name            string
+	comment         string
+	private         bool
+	vpc             *dnsv1alpha1.VPC
+	delegationSetId string
+	tags            map[string]string
+}
+
+// AsHostedZoneProvider returns p as a HostedZoneProvider, or a TerminalError
+// if the backend does not support managing zones.
+func AsHostedZoneProvider(p DNSProvider) (HostedZoneProvider, error) {
+	hp, ok := p.(HostedZoneProvider)
+	if !ok {
+		return nil, &TerminalError{Err: fmt.Errorf("provider does not support managing hosted zones")}
+	}
+	return hp, nil
+}
+
+// ConvergeHostedZone creates the zone when id is empty or does not exist any
+// more, and updates its comment and tags when they differ from spec. A zone
+// created for the owner whose id was not recorded is adopted instead of
+// created again. It returns the zone after the change.
+func ConvergeHostedZone(ctx context.Context, p HostedZoneProvider, id, owner string, spec dnsv1alpha1.HostedZoneSpec) (*HostedZone, error) {
+	desired, err := newHostedZoneConfig(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var current *HostedZone
+	if id != "" {
+		current, err = p.HostedZone(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if current == nil {
+		current, err = p.OwnedHostedZone(ctx, desired.name, owner)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if current == nil {
+		return p.CreateHostedZone(ctx, newCallerReference(owner), desired)
+	}
+	id = current.ID
+
+	if !current.HasName(desired.name) || current.Private != desired.private {
+		return nil, &TerminalError{Err: fmt.Errorf("zoneName and private of hosted zone %s can not be changed", id)}
+	}
+	if current.Comment != desired.comment {
+		if err := p.UpdateHostedZoneComment(ctx, id, desired.comment); err != nil {
+			return nil, err
+		}
+		current.Comment = desired.comment
+	}
+	if add, remove := tagChanges(desired.tags, current.Tags); 0 < len(add) || 0 < len(remove) {
+		if err := p.ChangeHostedZoneTags(ctx, id, add, remove); err != nil {
+			return nil, err
+		}
+		current.Tags = desired.tags
+	}
+	return current, nil
+}
+
+func newHostedZoneConfig(spec dnsv1alpha1.HostedZoneSpec) (hostedZoneConfig, error) {
+	config := hostedZoneConfig{
+		name:            spec.ZoneName,
+		comment:         spec.Comment,
+		private:         spec.Private,
+		delegationSetId: spec.DelegationSetID,
+		tags:            spec.Tags,
+	}
+	if spec.Private {
+		switch {
+		case len(spec.VPCs) == 0:
+			return config, &TerminalError{Err: fmt.Errorf("a vpc is required for private hosted zones")}
+		case spec.DelegationSetID != "":
+			return config, &TerminalError{Err: fmt.Errorf("private hosted zones can not use a delegation set")}
+		case spec.Delegation != nil:
+			return config, &TerminalError{Err: fmt.Errorf("private hosted zones can not be delegated")}
+		case spec.VPCs[0].Auth != nil:
+			return config, &TerminalError{Err: fmt.Errorf("the first vpc of a private hosted zone must belong to the account of the provider")}
+		}
+		config.vpc = &spec.VPCs[0]
+	}
+	return config, nil
+}
+
+// tagChanges returns the tags to add or overwrite and the keys to remove to
+// turn the live tags into the desired ones.
+func tagChanges(desired, live map[string]string) (map[string]string, []string) {
+	add := map[string]string{}
+	for k, v := range desired {
+		if lv, exist := live[k]; !exist || lv != v {
+			add[k] = v
+		}
+	}
+	remove := make([]string, 0)
+	for k := range live {
+		if _, exist := desired[k]; !exist {
+			remove = append(remove, k)
+		}
+	}
+	sort.Strings(remove)
+	return add, remove
+}
+
+// DelegationOwner returns the owner name of the NS records of zoneName in
+// its parent zone, or a TerminalError if zoneName is not a subdomain of it.
+func DelegationOwner(zoneName, parentZoneName string) (string, error) {
+	zone := strings.ToLower(strings.TrimSuffix(zoneName, "."))
+	suffix := "." + strings.ToLower(strings.TrimSuffix(parentZoneName, "."))
+	if !strings.HasSuffix(zone, suffix) {
+		return "", &TerminalError{Err: fmt.Errorf("%s is not a subdomain of %s", zoneName, parentZoneName)}
+	}
+	return strings.TrimSuffix(zone, suffix), nil
+}
+
+// DelegationRecord returns the NS records which delegate to the name servers.
+// ttl defaults to 172800.
+func DelegationRecord(nameServers []string, ttl *int32) dnsv1alpha1.ResourceRecordSpec {
+	t := defaultDelegationTtl
+	if ttl != nil {
+		t = *ttl
+	}
+	values := make([]string, len(nameServers))
+	for i, ns := range nameServers {
+		values[i] = strings.TrimSuffix(ns, ".") + "."
+	}
+	return dnsv1alpha1.ResourceRecordSpec{
+		Class:  "NS",
+		Ttl:    &t,
+		Rdatas: values,
+	}
+}
@@ -0,0 +1,210 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+
+	dnsv1alpha1 "github.com/ch1aki/dns-rr/api/v1alpha1"
+)
+
+func TestDelegationOwner(t *testing.T) {
+	tests := []struct {
+		zoneName   string
+		parentName string
+		want       string
+		wantErr    bool
+	}{
+		{zoneName: "team-a.example.com", parentName: "example.com", want: "team-a"},
+		{zoneName: "dev.team-a.Example.com.", parentName: "example.com.", want: "dev.team-a"},
+		{zoneName: "example.com", parentName: "example.com", wantErr: true},
+		{zoneName: "team-a.example.org", parentName: "example.com", wantErr: true},
+		{zoneName: "team-aexample.com", parentName: "example.com", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.zoneName, func(t *testing.T) {
+			got, err := DelegationOwner(tt.zoneName, tt.parentName)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DelegationOwner() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTagChanges(t *testing.T) {
+	add, remove := tagChanges(
+		map[string]string{"team": "a", "env": "prod"},
+		map[string]string{"team": "b", "owner": "x"},
+	)
+	if diff := cmp.Diff(add, map[string]string{"team": "a", "env": "prod"}); diff != "" {
+		t.Errorf("add differs: (-got +want)\n%s", diff)
+	}
+	if diff := cmp.Diff(remove, []string{"owner"}); diff != "" {
+		t.Errorf("remove differs: (-got +want)\n%s", diff)
+	}
+}
+
+func TestConvergeHostedZone(t *testing.T) {
+	defer func(f func(string) string) { newCallerReference = f }(newCallerReference)
+	newCallerReference = func(owner string) string { return owner + "-1" }
+
+	hostedZone := &types.HostedZone{
+		Id:     aws.String("/hostedzone/Z0123456789ABCDEFGHIJ"),
+		Name:   aws.String("team-a.example.com."),
+		Config: &types.HostedZoneConfig{Comment: aws.String("team a")},
+	}
+	delegationSet := &types.DelegationSet{NameServers: []string{"ns-2.awsdns-02.net", "ns-1.awsdns-01.com"}}
+	spec := dnsv1alpha1.HostedZoneSpec{ZoneName: "team-a.example.com", Comment: "team a", Tags: map[string]string{"team": "a"}}
+
+	ownedZone := *hostedZone
+	ownedZone.CallerReference = aws.String("uid-1-1")
+	otherZone := *hostedZone
+	otherZone.Id = aws.String("/hostedzone/ZOTHER")
+	otherZone.CallerReference = aws.String("uid-2-1")
+
+	tests := []struct {
+		name       string
+		id         string
+		deleted    bool
+		listed     []types.HostedZone
+		spec       dnsv1alpha1.HostedZoneSpec
+		liveTags   []types.Tag
+		wantCreate bool
+		wantCalls  func(m *MockRoute53API)
+		wantErr    bool
+	}{
+		{
+			name:       "create",
+			spec:       spec,
+			wantCreate: true,
+			wantCalls: func(m *MockRoute53API) {
+				m.EXPECT().ChangeTagsForResource(context.TODO(), &route53.ChangeTagsForResourceInput{
+					ResourceId:   aws.String("Z0123456789ABCDEFGHIJ"),
+					ResourceType: types.TagResourceTypeHostedzone,
+					AddTags:      []types.Tag{{Key: aws.String("team"), Value: aws.String("a")}},
+				}).Return(&route53.ChangeTagsForResourceOutput{}, nil)
+			},
+		},
+		{
+			name:     "adopt zone created before status was lost",
+			listed:   []types.HostedZone{otherZone, ownedZone},
+			spec:     spec,
+			liveTags: []types.Tag{{Key: aws.String("team"), Value: aws.String("a")}},
+		},
+		{
+			name:       "create again zone deleted outside",
+			id:         "Z0123456789ABCDEFGHIJ",
+			deleted:    true,
+			listed:     []types.HostedZone{otherZone},
+			spec:       spec,
+			wantCreate: true,
+			wantCalls: func(m *MockRoute53API) {
+				m.EXPECT().ChangeTagsForResource(context.TODO(), gomock.Any()).Return(&route53.ChangeTagsForResourceOutput{}, nil)
+			},
+		},
+		{
+			name:     "in sync",
+			id:       "Z0123456789ABCDEFGHIJ",
+			spec:     spec,
+			liveTags: []types.Tag{{Key: aws.String("team"), Value: aws.String("a")}},
+		},
+		{
+			name:     "update comment and tags",
+			id:       "Z0123456789ABCDEFGHIJ",
+			spec:     dnsv1alpha1.HostedZoneSpec{ZoneName: "team-a.example.com"},
+			liveTags: []types.Tag{{Key: aws.String("team"), Value: aws.String("a")}},
+			wantCalls: func(m *MockRoute53API) {
+				m.EXPECT().UpdateHostedZoneComment(context.TODO(), &route53.UpdateHostedZoneCommentInput{
+					Id:      aws.String("Z0123456789ABCDEFGHIJ"),
+					Comment: aws.String(""),
+				}).Return(&route53.UpdateHostedZoneCommentOutput{}, nil)
+				m.EXPECT().ChangeTagsForResource(context.TODO(), &route53.ChangeTagsForResourceInput{
+					ResourceId:    aws.String("Z0123456789ABCDEFGHIJ"),
+					ResourceType:  types.TagResourceTypeHostedzone,
+					RemoveTagKeys: []string{"team"},
+				}).Return(&route53.ChangeTagsForResourceOutput{}, nil)
+			},
+		},
+		{
+			name:    "change name",
+			id:      "Z0123456789ABCDEFGHIJ",
+			spec:    dnsv1alpha1.HostedZoneSpec{ZoneName: "team-b.example.com", Comment: "team a"},
+			wantErr: true,
+		},
+		{
+			name: "private with cross-account first vpc",
+			spec: dnsv1alpha1.HostedZoneSpec{ZoneName: "team-a.example.com", Private: true, VPCs: []dnsv1alpha1.VPC{
+				{ID: "vpc-remote", Region: "us-east-1", Auth: &dnsv1alpha1.AWSAuth{AssumeRole: &dnsv1alpha1.AWSAssumeRole{RoleARN: "arn:aws:iam::111111111111:role/dns"}}},
+			}},
+			wantErr: true,
+		},
+		{
+			name:    "private without vpc",
+			spec:    dnsv1alpha1.HostedZoneSpec{ZoneName: "team-a.example.com", Private: true},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+			r53api := NewMockRoute53API(controller)
+			if tt.deleted {
+				r53api.EXPECT().GetHostedZone(context.TODO(), &route53.GetHostedZoneInput{Id: aws.String(tt.id)}).
+					Return(nil, &types.NoSuchHostedZone{})
+			}
+			if (tt.id == "" || tt.deleted) && !tt.wantErr {
+				r53api.EXPECT().ListHostedZonesByName(context.TODO(), &route53.ListHostedZonesByNameInput{DNSName: aws.String("team-a.example.com.")}).
+					Return(&route53.ListHostedZonesByNameOutput{HostedZones: tt.listed}, nil)
+			}
+			if !tt.wantCreate && (tt.id != "" || 0 < len(tt.listed)) {
+				r53api.EXPECT().GetHostedZone(context.TODO(), &route53.GetHostedZoneInput{Id: aws.String("Z0123456789ABCDEFGHIJ")}).
+					Return(&route53.GetHostedZoneOutput{HostedZone: hostedZone, DelegationSet: delegationSet}, nil)
+				r53api.EXPECT().ListTagsForResource(context.TODO(), gomock.Any()).
+					Return(&route53.ListTagsForResourceOutput{ResourceTagSet: &types.ResourceTagSet{Tags: tt.liveTags}}, nil)
+			}
+			if tt.wantCreate {
+				r53api.EXPECT().CreateHostedZone(context.TODO(), &route53.CreateHostedZoneInput{
+					Name:             aws.String("team-a.example.com"),
+					CallerReference:  aws.String("uid-1-1"),
+					HostedZoneConfig: &types.HostedZoneConfig{Comment: aws.String("team a")},
+				}).Return(&route53.CreateHostedZoneOutput{HostedZone: hostedZone, DelegationSet: delegationSet}, nil)
+			}
+			if tt.wantCalls != nil {
+				tt.wantCalls(r53api)
+			}
+			p := Route53Provider{client: r53api}
+
+			got, err := ConvergeHostedZone(context.TODO(), p, tt.id, "uid-1", tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ConvergeHostedZone() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(got.NameServers, []string{"ns-1.awsdns-01.com", "ns-2.awsdns-02.net"}); diff != "" {
+				t.Errorf("name servers differ: (-got +want)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDelegationRecord(t *testing.T) {
+	got := DelegationRecord([]string{"ns-1.awsdns-01.com", "ns-2.awsdns-02.net."}, nil)
+	want := dnsv1alpha1.ResourceRecordSpec{
+		Class:  "NS",
+		Ttl:    aws.Int32(172800),
+		Rdatas: []string{"ns-1.awsdns-01.com.", "ns-2.awsdns-02.net."},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("differs: (-got +want)\n%s", diff)
+	}
+}
@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	recordOwnerPrefix = "dns-rr-owner: "
+
+	// ownerRecordTTL is the TTL of the TXT records which mark the owner of a record
+	ownerRecordTTL = 300
+
+	// maxLabelLength is the length limit of a label of a domain name.
+	maxLabelLength = 63
+	// ownerIdHashLength is the length of the hash which replaces a long id in
+	// the label of an owner record.
+	ownerIdHashLength = 32
+)
+
+// ownerIdRegexp matches the ids which are used as they are in the label of an owner record.
+var ownerIdRegexp = regexp.MustCompile(`^[a-z0-9-]*$`)
+
+// ConflictError is returned when records exist which are not owned by the ResourceRecord.
+// These records are never changed or deleted.
+type ConflictError struct {
+	FQDNs []string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("records are not owned by this resource: %s", strings.Join(e.FQDNs, ", "))
+}
+
+// liveState is the live records of a ResourceRecord and the TXT records of their owners.
+type liveState struct {
+	// records keyed by owner name
+	records map[string]Endpoint
+	// owner TXT records keyed by the owner name of the record they belong to
+	ownerRecords map[string]Endpoint
+}
+
+func fetchLiveState(ctx context.Context, p DNSProvider, names []string, class string, id *string) (liveState, error) {
+	records, ownerRecords, err := p.Records(ctx, names, class, id)
+	if err != nil {
+		return liveState{}, err
+	}
+	return liveState{records: records, ownerRecords: ownerRecords}, nil
+}
+
+// ownedNames splits names into the names owned by ownerId and the FQDNs of the
+// conflicting names. A name is owned when its owner TXT record names ownerId,
+// or when no owner TXT record exists and either no record exists yet, the
+// name was managed before owner records were written or adopt is set.
+func ownedNames(names []string, zoneName string, ownerId string, state liveState, managed []string, adopt bool) ([]string, []string) {
+	wasManaged := make(map[string]bool, len(managed))
+	for _, fqdn := range managed {
+		wasManaged[fqdn] = true
+	}
+
+	owned := make([]string, 0, len(names))
+	conflicts := make([]string, 0)
+	for _, name := range names {
+		fqdn := buildFQDN(name, zoneName)
+		txt, hasOwner := state.ownerRecords[name]
+		_, hasRecord := state.records[name]
+		switch {
+		case hasOwner && isOwnerOfRecord(txt, ownerId):
+		case !hasOwner && (!hasRecord || wasManaged[fqdn] || adopt):
+		default:
+			conflicts = append(conflicts, fqdn)
+			continue
+		}
+		owned = append(owned, name)
+	}
+	return owned, conflicts
+}
+
+// ownerChanges creates the missing owner TXT records of names.
+func ownerChanges(names []string, zoneName string, class string, id string, ownerId string, state liveState) []Change {
+	changes := make([]Change, 0)
+	for _, name := range names {
+		if _, exist := state.ownerRecords[name]; !exist {
+			changes = append(changes, Change{
+				Action:   ChangeActionCreate,
+				Endpoint: ownerEndpoint(name, zoneName, class, id, ownerId),
+			})
+		}
+	}
+	return changes
+}
+
+// ownerRecordName returns the name of the TXT record which marks the owner of
+// the record of class with set identifier id at name. An id which is not a
+// lowercase label, e.g. with spaces or capitals which would make ids differing
+// only by case share the name, or which does not fit in the label is replaced
+// by its hash.
+func ownerRecordName(name, class, id string) string {
+	prefix := "_dns-rr-" + strings.ToLower(class)
+	label := prefix
+	if id != "" {
+		label = label + "-" + id
+	}
+	if maxLabelLength < len(label) || !ownerIdRegexp.MatchString(id) {
+		sum := sha256.Sum256([]byte(id))
+		label = prefix + "-" + hex.EncodeToString(sum[:])[:ownerIdHashLength]
+	}
+	return label + "." + name
+}
+
+func ownerEndpoint(name, zoneName, class, id, ownerId string) Endpoint {
+	return Endpoint{
+		DNSName: buildFQDN(ownerRecordName(name, class, id), zoneName),
+		Class:   "TXT",
+		Rdata:   []string{buildOwnerRecordValue(ownerId)},
+		Ttl:     ownerRecordTTL,
+	}
+}
+
+func isOwnerOfRecord(txt Endpoint, ownerId string) bool {
+	want := buildOwnerRecordValue(ownerId)
+	for _, v := range txt.Rdata {
+		if v == want {
+			return true
+		}
+	}
+	return false
+}
+
+func buildOwnerRecordValue(ownerId string) string {
+	return strconv.Quote(recordOwnerPrefix + ownerId)
+}
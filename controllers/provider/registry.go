@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dnsv1alpha1 "github.com/ch1aki/dns-rr/api/v1alpha1"
+)
+
+// NewFunc builds a DNSProvider from the Provider object.
+type NewFunc func(ctx context.Context, p *dnsv1alpha1.Provider, c client.Client) (DNSProvider, error)
+
+type backend struct {
+	// configured reports whether the backend block of the ProviderSpec is set
+	configured func(spec *dnsv1alpha1.ProviderSpec) bool
+	new        NewFunc
+}
+
+var backends = map[string]backend{}
+
+// Register adds a DNS backend which is selected when configured returns true
+// for the ProviderSpec. It is meant to be called from init.
+func Register(name string, configured func(spec *dnsv1alpha1.ProviderSpec) bool, fn NewFunc) {
+	if _, exist := backends[name]; exist {
+		panic(fmt.Sprintf("provider %s is already registered", name))
+	}
+	backends[name] = backend{configured: configured, new: fn}
+}
+
+// New builds the DNSProvider of the backend configured in the Provider object.
+// Exactly one backend must be configured.
+func New(ctx context.Context, p *dnsv1alpha1.Provider, c client.Client) (DNSProvider, error) {
+	names := make([]string, 0, 1)
+	for name, b := range backends {
+		if b.configured(&p.Spec) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	switch len(names) {
+	case 0:
+		return nil, &TerminalError{Err: fmt.Errorf("provider %s has no backend configured", p.Name)}
+	case 1:
+		return backends[names[0]].new(ctx, p, c)
+	default:
+		return nil, &TerminalError{Err: fmt.Errorf("provider %s has multiple backends configured: %v", p.Name, names)}
+	}
+}
@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dnsv1alpha1 "github.com/ch1aki/dns-rr/api/v1alpha1"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    dnsv1alpha1.ProviderSpec
+		wantErr bool
+	}{
+		{
+			name:    "no backend",
+			spec:    dnsv1alpha1.ProviderSpec{},
+			wantErr: true,
+		},
+		{
+			name: "route53 without region",
+			spec: dnsv1alpha1.ProviderSpec{
+				Route53: &dnsv1alpha1.Route53Provider{
+					HostedZoneID:   "Z0123456789ABCDEFGHIJ",
+					HostedZoneName: "example.com",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "route53",
+			spec: dnsv1alpha1.ProviderSpec{
+				Route53: &dnsv1alpha1.Route53Provider{
+					HostedZoneID:   "Z0123456789ABCDEFGHIJ",
+					HostedZoneName: "example.com",
+					Region:         "ap-northeast-1",
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &dnsv1alpha1.Provider{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+				Spec:       tt.spec,
+			}
+			got, err := New(context.TODO(), p, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !IsTerminal(err) {
+					t.Errorf("New() error = %v, want terminal error", err)
+				}
+				return
+			}
+			if got.ZoneName() != tt.spec.Route53.HostedZoneName {
+				t.Errorf("ZoneName() = %v, want %v", got.ZoneName(), tt.spec.Route53.HostedZoneName)
+			}
+		})
+	}
+}
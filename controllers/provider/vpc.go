@@ -0,0 +1,106 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	dnsv1alpha1 "github.com/ch1aki/dns-rr/api/v1alpha1"
+)
+
+// VPCAssociationProvider is implemented by backends whose private zones are
+// associated with VPCs.
+type VPCAssociationProvider interface {
+	// ZoneVPCs returns the VPCs the zone is associated with.
+	ZoneVPCs(ctx context.Context, zoneId string) ([]dnsv1alpha1.VPC, error)
+
+	// AssociateVPC associates the VPC with the zone.
+	AssociateVPC(ctx context.Context, zoneId string, vpc dnsv1alpha1.VPC) error
+
+	// DisassociateVPC disassociates the VPC from the zone.
+	DisassociateVPC(ctx context.Context, zoneId string, vpc dnsv1alpha1.VPC) error
+
+	// AuthorizeVPCAssociation allows the account of the VPC to associate it
+	// with the zone of this account.
+	AuthorizeVPCAssociation(ctx context.Context, zoneId string, vpc dnsv1alpha1.VPC) error
+
+	// RevokeVPCAssociationAuthorization removes the authorization, which is
+	// not needed once the VPC is associated.
+	RevokeVPCAssociationAuthorization(ctx context.Context, zoneId string, vpc dnsv1alpha1.VPC) error
+}
+
+// VPCAssociation is a VPC a zone should be associated with.
+type VPCAssociation struct {
+	VPC dnsv1alpha1.VPC
+
+	// Owner is the backend of the account which owns the VPC, or nil when it
+	// is the account of the zone.
+	Owner VPCAssociationProvider
+}
+
+// AsVPCAssociationProvider returns p as a VPCAssociationProvider, or a
+// TerminalError if the backend does not support VPC associations.
+func AsVPCAssociationProvider(p DNSProvider) (VPCAssociationProvider, error) {
+	vp, ok := p.(VPCAssociationProvider)
+	if !ok {
+		return nil, &TerminalError{Err: fmt.Errorf("provider does not support vpc associations")}
+	}
+	return vp, nil
+}
+
+// ConvergeVPCAssociations associates the zone with the desired VPCs and
+// disassociates the others. VPCs in other accounts are associated by their
+// owner after the account of the zone authorized it. New VPCs are associated
+// first, as a private zone can not lose its last VPC. Nothing is changed when
+// desired is empty.
+func ConvergeVPCAssociations(ctx context.Context, p VPCAssociationProvider, zoneId string, desired []VPCAssociation) error {
+	if len(desired) == 0 {
+		return nil
+	}
+
+	live, err := p.ZoneVPCs(ctx, zoneId)
+	if err != nil {
+		return err
+	}
+	associated := make(map[string]bool, len(live))
+	for _, vpc := range live {
+		associated[vpcKey(vpc)] = true
+	}
+
+	wanted := make(map[string]bool, len(desired))
+	for _, a := range desired {
+		wanted[vpcKey(a.VPC)] = true
+		if associated[vpcKey(a.VPC)] {
+			continue
+		}
+		if a.Owner == nil {
+			if err := p.AssociateVPC(ctx, zoneId, a.VPC); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// cross-account association
+		if err := p.AuthorizeVPCAssociation(ctx, zoneId, a.VPC); err != nil {
+			return err
+		}
+		if err := a.Owner.AssociateVPC(ctx, zoneId, a.VPC); err != nil {
+			return err
+		}
+		if err := p.RevokeVPCAssociationAuthorization(ctx, zoneId, a.VPC); err != nil {
+			return err
+		}
+	}
+
+	for _, vpc := range live {
+		if !wanted[vpcKey(vpc)] {
+			if err := p.DisassociateVPC(ctx, zoneId, vpc); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func vpcKey(vpc dnsv1alpha1.VPC) string {
+	return vpc.Region + "/" + vpc.ID
+}
@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/golang/mock/gomock"
+
+	dnsv1alpha1 "github.com/ch1aki/dns-rr/api/v1alpha1"
+)
+
+func TestConvergeVPCAssociations(t *testing.T) {
+	const zoneId = "Z0123456789ABCDEFGHIJ"
+	local := dnsv1alpha1.VPC{ID: "vpc-local", Region: "ap-northeast-1"}
+	remote := dnsv1alpha1.VPC{ID: "vpc-remote", Region: "us-east-1", Auth: &dnsv1alpha1.AWSAuth{AssumeRole: &dnsv1alpha1.AWSAssumeRole{RoleARN: "arn:aws:iam::111111111111:role/dns"}}}
+	stale := dnsv1alpha1.VPC{ID: "vpc-stale", Region: "ap-northeast-1"}
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	zoneApi := NewMockRoute53API(controller)
+	vpcApi := NewMockRoute53API(controller)
+
+	gomock.InOrder(
+		zoneApi.EXPECT().GetHostedZone(context.TODO(), &route53.GetHostedZoneInput{Id: aws.String(zoneId)}).
+			Return(&route53.GetHostedZoneOutput{VPCs: []types.VPC{*route53VPC(local), *route53VPC(stale)}}, nil),
+		zoneApi.EXPECT().CreateVPCAssociationAuthorization(context.TODO(), &route53.CreateVPCAssociationAuthorizationInput{HostedZoneId: aws.String(zoneId), VPC: route53VPC(remote)}).
+			Return(&route53.CreateVPCAssociationAuthorizationOutput{}, nil),
+		vpcApi.EXPECT().AssociateVPCWithHostedZone(context.TODO(), &route53.AssociateVPCWithHostedZoneInput{HostedZoneId: aws.String(zoneId), VPC: route53VPC(remote)}).
+			Return(&route53.AssociateVPCWithHostedZoneOutput{}, nil),
+		zoneApi.EXPECT().DeleteVPCAssociationAuthorization(context.TODO(), &route53.DeleteVPCAssociationAuthorizationInput{HostedZoneId: aws.String(zoneId), VPC: route53VPC(remote)}).
+			Return(&route53.DeleteVPCAssociationAuthorizationOutput{}, nil),
+		zoneApi.EXPECT().DisassociateVPCFromHostedZone(context.TODO(), &route53.DisassociateVPCFromHostedZoneInput{HostedZoneId: aws.String(zoneId), VPC: route53VPC(stale)}).
+			Return(nil, &types.VPCAssociationNotFound{Message: aws.String("not found")}),
+	)
+
+	p := Route53Provider{client: zoneApi, hostedZoneId: zoneId}
+	owner := Route53Provider{client: vpcApi}
+	err := ConvergeVPCAssociations(context.TODO(), p, zoneId, []VPCAssociation{{VPC: local}, {VPC: remote, Owner: owner}})
+	if err != nil {
+		t.Fatalf("ConvergeVPCAssociations() error = %v", err)
+	}
+}
+
+func TestConvergeVPCAssociationsUnmanaged(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+	p := Route53Provider{client: NewMockRoute53API(controller)}
+
+	if err := ConvergeVPCAssociations(context.TODO(), p, "Z0123456789ABCDEFGHIJ", nil); err != nil {
+		t.Fatalf("ConvergeVPCAssociations() error = %v", err)
+	}
+}
+
+func TestNewVPCOwner(t *testing.T) {
+	tests := []struct {
+		name    string
+		vpc     dnsv1alpha1.VPC
+		wantErr bool
+	}{
+		{
+			name:    "no credentials",
+			vpc:     dnsv1alpha1.VPC{ID: "vpc-remote", Region: "us-east-1"},
+			wantErr: true,
+		},
+		{
+			name: "assume role without a hosted zone",
+			vpc:  dnsv1alpha1.VPC{ID: "vpc-remote", Region: "us-east-1", Auth: &dnsv1alpha1.AWSAuth{AssumeRole: &dnsv1alpha1.AWSAssumeRole{RoleARN: "arn:aws:iam::111111111111:role/dns"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			owner, err := NewVPCOwner(context.TODO(), "default", tt.vpc, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewVPCOwner() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && owner == nil {
+				t.Errorf("expected an owner")
+			}
+		})
+	}
+}
@@ -18,14 +18,40 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"time"
 
-	"k8s.io/apimachinery/pkg/api/errors"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	dnsv1alpha1 "github.com/ch1aki/dns-rr/api/v1alpha1"
+	"github.com/ch1aki/dns-rr/controllers/provider"
+)
+
+const (
+	// providerInUsePollInterval is how often the deletion of a provider checks
+	// whether ResourceRecords still reference it.
+	providerInUsePollInterval = 30 * time.Second
+
+	// providerValidationInterval is how often a provider is validated again,
+	// so that credentials revoked in the provider are noticed.
+	providerValidationInterval = 10 * time.Minute
+
+	secretField = ".spec.route53.auth.secretRef"
 )
 
 // ProviderReconciler reconciles a Provider object
@@ -37,13 +63,17 @@ type ProviderReconciler struct {
 //+kubebuilder:rbac:groups=dns.ch1aki.github.io,resources=providers,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=dns.ch1aki.github.io,resources=providers/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=dns.ch1aki.github.io,resources=providers/finalizers,verbs=update
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups=dns.ch1aki.github.io,resources=resourcerecords,verbs=get;list;watch
+//+kubebuilder:rbac:groups=dns.ch1aki.github.io,resources=hostedzones,verbs=get;list;watch
+//+kubebuilder:rbac:groups=dns.ch1aki.github.io,resources=healthchecks,verbs=get;list;watch
+//+kubebuilder:rbac:groups=dns.ch1aki.github.io,resources=cidrcollections,verbs=get;list;watch
 
-// Reconcile is part of the main kubernetes reconciliation loop which aims to
-// move the current state of the cluster closer to the desired state.
-// TODO(user): Modify the Reconcile function to compare the state specified by
-// the Provider object against the actual cluster state, and then
-// perform operations to make the cluster state reflect the state specified by
-// the user.
+// Reconcile validates the referenced secrets, the credentials and the hosted
+// zone of the Provider and reports the result in its conditions. The Provider
+// is not deleted while ResourceRecords, HostedZones, HealthChecks or
+// CidrCollections reference it, so that they can still be cleaned up in the
+// provider.
 //
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.13.0/pkg/reconcile
@@ -52,7 +82,8 @@ func (r *ProviderReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 
 	var p dnsv1alpha1.Provider
 	err := r.Get(ctx, req.NamespacedName, &p)
-	if errors.IsNotFound(err) {
+	if apierrors.IsNotFound(err) {
+		provider.ForgetCredentials(req.NamespacedName)
 		return ctrl.Result{}, nil
 	}
 	if err != nil {
@@ -61,18 +92,229 @@ func (r *ProviderReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 	}
 
 	if !p.ObjectMeta.DeletionTimestamp.IsZero() {
+		return r.reconcileDelete(ctx, &p)
+	}
+
+	if err := addFinalizer(ctx, r, &p); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// validate secrets and region, and look the hosted zone up again instead
+	// of trusting the cached id
+	p.Status.HostedZoneID = ""
+	dnsProvider, err := provider.New(ctx, &p, r.Client)
+	switch {
+	case err == nil:
+	case errors.Is(err, provider.ErrZoneNotFound):
+		setProviderCondition(&p, dnsv1alpha1.ProviderConditionCredentialsValid, metav1.ConditionTrue, "Valid", "credentials are accepted")
+		setProviderCondition(&p, dnsv1alpha1.ProviderConditionHostedZoneValid, metav1.ConditionFalse, "HostedZoneNotFound", err.Error())
+		return r.validationFailed(ctx, &p, "HostedZoneNotFound", err)
+	case errors.Is(err, provider.ErrZoneAmbiguous):
+		setProviderCondition(&p, dnsv1alpha1.ProviderConditionCredentialsValid, metav1.ConditionTrue, "Valid", "credentials are accepted")
+		setProviderCondition(&p, dnsv1alpha1.ProviderConditionHostedZoneValid, metav1.ConditionFalse, "HostedZoneAmbiguous", err.Error())
+		return r.validationFailed(ctx, &p, "HostedZoneAmbiguous", err)
+	case provider.IsAuthError(err):
+		setProviderCondition(&p, dnsv1alpha1.ProviderConditionCredentialsValid, metav1.ConditionFalse, "InvalidCredentials", err.Error())
+		return r.validationFailed(ctx, &p, "InvalidCredentials", err)
+	default:
+		logger.Error(err, "failed initialize client")
+		setProviderCondition(&p, dnsv1alpha1.ProviderConditionCredentialsValid, metav1.ConditionFalse, "ClientError", err.Error())
+		return r.validationFailed(ctx, &p, "ClientError", err)
+	}
+
+	// validate credentials and hosted zone
+	zone, err := dnsProvider.Zone(ctx)
+	switch {
+	case err == nil:
+		setProviderCondition(&p, dnsv1alpha1.ProviderConditionCredentialsValid, metav1.ConditionTrue, "Valid", "credentials are accepted")
+	case provider.IsAuthError(err):
+		setProviderCondition(&p, dnsv1alpha1.ProviderConditionCredentialsValid, metav1.ConditionFalse, "InvalidCredentials", err.Error())
+		return r.validationFailed(ctx, &p, "InvalidCredentials", err)
+	case errors.Is(err, provider.ErrZoneMismatch):
+		setProviderCondition(&p, dnsv1alpha1.ProviderConditionCredentialsValid, metav1.ConditionTrue, "Valid", "credentials are accepted")
+		setProviderCondition(&p, dnsv1alpha1.ProviderConditionHostedZoneValid, metav1.ConditionFalse, "HostedZoneMismatch", err.Error())
+		return r.validationFailed(ctx, &p, "HostedZoneMismatch", err)
+	case provider.IsTerminal(err):
+		setProviderCondition(&p, dnsv1alpha1.ProviderConditionCredentialsValid, metav1.ConditionTrue, "Valid", "credentials are accepted")
+		setProviderCondition(&p, dnsv1alpha1.ProviderConditionHostedZoneValid, metav1.ConditionFalse, "HostedZoneNotFound", err.Error())
+		return r.validationFailed(ctx, &p, "HostedZoneNotFound", err)
+	default:
+		logger.Error(err, "failed get hosted zone")
+		return r.validationFailed(ctx, &p, "ValidationFailed", err)
+	}
+
+	p.Status.PrivateZone = zone.Private
+	p.Status.HostedZoneID = zone.ID
+	setProviderCondition(&p, dnsv1alpha1.ProviderConditionHostedZoneValid, metav1.ConditionTrue, "Valid", fmt.Sprintf("hosted zone %s found", zone.Name))
+
+	// associate the private zone with the declared vpcs
+	if p.Spec.Route53 != nil && 0 < len(p.Spec.Route53.VPCs) {
+		if err := r.reconcileVPCs(ctx, &p, dnsProvider, zone); err != nil {
+			logger.Error(err, "failed associate vpcs")
+			setProviderCondition(&p, dnsv1alpha1.ProviderConditionVPCsAssociated, metav1.ConditionFalse, "VPCAssociationFailed", err.Error())
+			return r.validationFailed(ctx, &p, "VPCAssociationFailed", err)
+		}
+		setProviderCondition(&p, dnsv1alpha1.ProviderConditionVPCsAssociated, metav1.ConditionTrue, "Associated", "hosted zone is associated with the vpcs")
+	} else {
+		meta.RemoveStatusCondition(&p.Status.Conditions, dnsv1alpha1.ProviderConditionVPCsAssociated)
+	}
+
+	setProviderCondition(&p, dnsv1alpha1.ProviderConditionReady, metav1.ConditionTrue, "Valid", "provider is ready")
+	if err := r.Status().Update(ctx, &p); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: providerValidationInterval}, nil
+}
+
+func (r *ProviderReconciler) reconcileDelete(ctx context.Context, p *dnsv1alpha1.Provider) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(p, finalizer) {
+		provider.ForgetCredentials(client.ObjectKeyFromObject(p))
 		return ctrl.Result{}, nil
 	}
 
-	// TODO(validation):
-	// TODO(reconcile):
+	// the provider can not be deleted while objects need it to clean up
+	inUse, err := r.inUse(ctx, p)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if inUse != "" {
+		setProviderCondition(p, dnsv1alpha1.ProviderConditionReady, metav1.ConditionFalse, "ProviderInUse", inUse)
+		if err := r.Status().Update(ctx, p); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: providerInUsePollInterval}, nil
+	}
+
+	provider.ForgetCredentials(client.ObjectKeyFromObject(p))
+	return removeFinalizer(ctx, r, p)
+}
+
+// inUse describes the objects which still reference the Provider, or returns
+// an empty string when there are none.
+func (r *ProviderReconciler) inUse(ctx context.Context, p *dnsv1alpha1.Provider) (string, error) {
+	references := []struct {
+		kind  string
+		list  client.ObjectList
+		field string
+	}{
+		{kind: "ResourceRecords", list: &dnsv1alpha1.ResourceRecordList{}, field: providerField},
+		{kind: "HostedZones", list: &dnsv1alpha1.HostedZoneList{}, field: providerField},
+		{kind: "HostedZones", list: &dnsv1alpha1.HostedZoneList{}, field: delegationProviderField},
+		{kind: "HealthChecks", list: &dnsv1alpha1.HealthCheckList{}, field: providerField},
+		{kind: "CidrCollections", list: &dnsv1alpha1.CidrCollectionList{}, field: providerField},
+	}
+	for _, ref := range references {
+		if err := r.List(ctx, ref.list, client.InNamespace(p.Namespace), client.MatchingFields{ref.field: p.Name}); err != nil {
+			return "", err
+		}
+		items, err := meta.ExtractList(ref.list)
+		if err != nil {
+			return "", err
+		}
+		if 0 < len(items) {
+			return fmt.Sprintf("provider is used by %d %s, e.g. %s", len(items), ref.kind, items[0].(client.Object).GetName()), nil
+		}
+	}
+	return "", nil
+}
+
+// reconcileVPCs associates the private zone with the VPCs of the spec and
+// disassociates the others.
+func (r *ProviderReconciler) reconcileVPCs(ctx context.Context, p *dnsv1alpha1.Provider, dnsProvider provider.DNSProvider, zone *provider.Zone) error {
+	if !zone.Private {
+		return &provider.TerminalError{Err: fmt.Errorf("hosted zone %s is not a private hosted zone", zone.Name)}
+	}
+	vpcProvider, err := provider.AsVPCAssociationProvider(dnsProvider)
+	if err != nil {
+		return err
+	}
+	associations, err := vpcAssociations(ctx, r.Client, p.Namespace, p.Spec.Route53.VPCs)
+	if err != nil {
+		return err
+	}
+	return provider.ConvergeVPCAssociations(ctx, vpcProvider, zone.ID, associations)
+}
+
+// validationFailed marks the Provider not ready. Retryable errors are returned
+// so that the request is requeued with backoff, terminal errors are validated
+// again after the interval.
+func (r *ProviderReconciler) validationFailed(ctx context.Context, p *dnsv1alpha1.Provider, reason string, err error) (ctrl.Result, error) {
+	setProviderCondition(p, dnsv1alpha1.ProviderConditionReady, metav1.ConditionFalse, reason, err.Error())
+	result, err := syncFailed(err, r.Status().Update(ctx, p))
+	if err == nil {
+		result.RequeueAfter = providerValidationInterval
+	}
+	return result, err
+}
 
-	return ctrl.Result{}, nil
+func setProviderCondition(p *dnsv1alpha1.Provider, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	setCondition(&p.Status.Conditions, &p.Status.ObservedGeneration, p.Generation, conditionType, status, reason, message)
+}
+
+// providerSecrets returns the keys of the Secrets the credentials of the
+// Provider and of its VPCs are read from.
+func providerSecrets(p *dnsv1alpha1.Provider) []string {
+	if p.Spec.Route53 == nil {
+		return nil
+	}
+	auths := []*dnsv1alpha1.AWSAuth{&p.Spec.Route53.Auth}
+	for _, vpc := range p.Spec.Route53.VPCs {
+		if vpc.Auth != nil {
+			auths = append(auths, vpc.Auth)
+		}
+	}
+
+	var keys []string
+	seen := map[string]bool{}
+	for _, auth := range auths {
+		if auth.SecretRef == nil {
+			continue
+		}
+		for _, selector := range []dnsv1alpha1.SecretKeySelector{auth.SecretRef.AccessKeyID, auth.SecretRef.SecretAccessKey} {
+			namespace := p.Namespace
+			if selector.Namespace != nil {
+				namespace = *selector.Namespace
+			}
+			key := types.NamespacedName{Namespace: namespace, Name: selector.Name}.String()
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *ProviderReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &dnsv1alpha1.Provider{}, secretField, func(rawObj client.Object) []string {
+		return providerSecrets(rawObj.(*dnsv1alpha1.Provider))
+	}); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&dnsv1alpha1.Provider{}).
+		For(&dnsv1alpha1.Provider{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(
+			&source.Kind{Type: &corev1.Secret{}},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsForSecret),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
 		Complete(r)
 }
+
+// findObjectsForSecret returns the requests of the Providers whose credentials
+// are read from the secret, so that rotated keys are validated.
+func (r *ProviderReconciler) findObjectsForSecret(secret client.Object) []reconcile.Request {
+	var providers dnsv1alpha1.ProviderList
+	if err := r.List(context.TODO(), &providers, client.MatchingFields{secretField: client.ObjectKeyFromObject(secret).String()}); err != nil {
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, len(providers.Items))
+	for i, item := range providers.Items {
+		requests[i] = reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&item)}
+	}
+	return requests
+}
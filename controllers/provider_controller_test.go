@@ -0,0 +1,146 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dnsv1alpha1 "github.com/ch1aki/dns-rr/api/v1alpha1"
+	"github.com/ch1aki/dns-rr/controllers/provider"
+)
+
+func TestProviderSecrets(t *testing.T) {
+	other := "other"
+	secretRef := func(accessKeyId, secretAccessKey string, namespace *string) *dnsv1alpha1.AWSAuthSecretRef {
+		return &dnsv1alpha1.AWSAuthSecretRef{
+			AccessKeyID:     dnsv1alpha1.SecretKeySelector{Name: accessKeyId, Namespace: namespace, Key: "id"},
+			SecretAccessKey: dnsv1alpha1.SecretKeySelector{Name: secretAccessKey, Namespace: namespace, Key: "secret"},
+		}
+	}
+
+	tests := []struct {
+		name string
+		spec dnsv1alpha1.ProviderSpec
+		want []string
+	}{
+		{
+			name: "no route53",
+			want: nil,
+		},
+		{
+			name: "default credentials",
+			spec: dnsv1alpha1.ProviderSpec{Route53: &dnsv1alpha1.Route53Provider{}},
+			want: nil,
+		},
+		{
+			name: "one secret for both keys",
+			spec: dnsv1alpha1.ProviderSpec{Route53: &dnsv1alpha1.Route53Provider{
+				Auth: dnsv1alpha1.AWSAuth{SecretRef: secretRef("aws", "aws", nil)},
+			}},
+			want: []string{"default/aws"},
+		},
+		{
+			name: "secrets in another namespace",
+			spec: dnsv1alpha1.ProviderSpec{Route53: &dnsv1alpha1.Route53Provider{
+				Auth: dnsv1alpha1.AWSAuth{SecretRef: secretRef("aws-id", "aws-secret", &other)},
+			}},
+			want: []string{"other/aws-id", "other/aws-secret"},
+		},
+		{
+			name: "secrets of vpcs",
+			spec: dnsv1alpha1.ProviderSpec{Route53: &dnsv1alpha1.Route53Provider{
+				Auth: dnsv1alpha1.AWSAuth{SecretRef: secretRef("aws", "aws", nil)},
+				VPCs: []dnsv1alpha1.VPC{
+					{ID: "vpc-1"},
+					{ID: "vpc-2", Auth: &dnsv1alpha1.AWSAuth{SecretRef: secretRef("aws", "aws", nil)}},
+					{ID: "vpc-3", Auth: &dnsv1alpha1.AWSAuth{SecretRef: secretRef("vpc", "vpc", &other)}},
+				},
+			}},
+			want: []string{"default/aws", "other/vpc"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &dnsv1alpha1.Provider{ObjectMeta: metav1.ObjectMeta{Name: "p", Namespace: "default"}, Spec: tt.spec}
+			if diff := cmp.Diff(providerSecrets(p), tt.want); diff != "" {
+				t.Errorf("differs: (-got +want)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidationFailed(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := dnsv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	retryable := errors.New("throttled")
+
+	tests := []struct {
+		name    string
+		err     error
+		want    ctrl.Result
+		wantErr error
+	}{
+		{
+			name: "terminal error is revalidated on the interval",
+			err:  &provider.TerminalError{Err: errors.New("access denied")},
+			want: ctrl.Result{RequeueAfter: providerValidationInterval},
+		},
+		{
+			name:    "retryable error is requeued with backoff",
+			err:     retryable,
+			want:    ctrl.Result{},
+			wantErr: retryable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &dnsv1alpha1.Provider{ObjectMeta: metav1.ObjectMeta{Name: "p", Namespace: "default", Generation: 1}}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(p.DeepCopy()).Build()
+			r := &ProviderReconciler{Client: c, Scheme: scheme}
+			if err := r.Get(context.TODO(), client.ObjectKeyFromObject(p), p); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := r.validationFailed(context.TODO(), p, "ValidationFailed", tt.err)
+			if got != tt.want {
+				t.Errorf("validationFailed() = %v, want %v", got, tt.want)
+			}
+			if err != tt.wantErr {
+				t.Errorf("validationFailed() error = %v, want %v", err, tt.wantErr)
+			}
+
+			var stored dnsv1alpha1.Provider
+			if err := r.Get(context.TODO(), client.ObjectKeyFromObject(p), &stored); err != nil {
+				t.Fatal(err)
+			}
+			if len(stored.Status.Conditions) != 1 || stored.Status.Conditions[0].Reason != "ValidationFailed" {
+				t.Errorf("conditions = %v, want Ready with reason ValidationFailed", stored.Status.Conditions)
+			}
+		})
+	}
+}
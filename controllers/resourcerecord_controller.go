@@ -18,14 +18,21 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"time"
 
-	"k8s.io/apimachinery/pkg/api/errors"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
@@ -37,13 +44,26 @@ import (
 )
 
 const (
-	ownerField = ".spec.ownerRef"
+	ownerField          = ".spec.ownerRef"
+	providerField       = ".spec.providerRef"
+	cidrCollectionField = ".spec.cidrRouting.collectionRef"
+	healthCheckField    = ".spec.healthCheckRef"
+
+	// propagationPollInterval is how often a pending change is checked.
+	propagationPollInterval = 10 * time.Second
+
+	// resyncInterval is how often records in sync are compared with the
+	// provider again, so that changes made outside of the controller are repaired.
+	resyncInterval = 10 * time.Minute
 )
 
 // ResourceRecordReconciler reconciles a ResourceRecord object
 type ResourceRecordReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+
+	// OwnerID identifies this controller instance in the owner TXT records
+	OwnerID string
 }
 
 //+kubebuilder:rbac:groups=dns.ch1aki.github.io,resources=resourcerecords,verbs=get;list;watch;create;update;patch;delete
@@ -52,6 +72,8 @@ type ResourceRecordReconciler struct {
 //+kubebuilder:rbac:groups=dns.ch1aki.github.io,resources=owners,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=dns.ch1aki.github.io,resources=owners/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=dns.ch1aki.github.io,resources=owners/finalizers,verbs=update
+//+kubebuilder:rbac:groups=dns.ch1aki.github.io,resources=cidrcollections,verbs=get;list;watch
+//+kubebuilder:rbac:groups=dns.ch1aki.github.io,resources=healthchecks,verbs=get;list;watch
 //+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
 
 func (r *ResourceRecordReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -59,7 +81,7 @@ func (r *ResourceRecordReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 
 	var rr dnsv1alpha1.ResourceRecord
 	err := r.Get(ctx, req.NamespacedName, &rr)
-	if errors.IsNotFound(err) {
+	if apierrors.IsNotFound(err) {
 		return ctrl.Result{}, nil
 	}
 	if err != nil {
@@ -68,7 +90,11 @@ func (r *ResourceRecordReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	}
 
 	if !rr.ObjectMeta.DeletionTimestamp.IsZero() {
-		return ctrl.Result{}, nil
+		return r.reconcileDelete(ctx, &rr)
+	}
+
+	if err := addFinalizer(ctx, r, &rr); err != nil {
+		return ctrl.Result{}, err
 	}
 
 	// main logic
@@ -76,8 +102,8 @@ func (r *ResourceRecordReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	// get owner object
 	var owner dnsv1alpha1.Owner
 	err = r.Get(ctx, client.ObjectKey{Namespace: rr.Namespace, Name: rr.Spec.OwnerRef}, &owner)
-	if errors.IsNotFound(err) {
-		return ctrl.Result{}, nil
+	if apierrors.IsNotFound(err) {
+		return ctrl.Result{}, r.setNotReady(ctx, &rr, "OwnerNotFound", fmt.Sprintf("owner %s not found", rr.Spec.OwnerRef))
 	}
 	if err != nil {
 		logger.Error(err, "unable to get ResourceRecord", "name", rr.Namespace+"/"+rr.Spec.OwnerRef)
@@ -87,8 +113,8 @@ func (r *ResourceRecordReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	// get provider object
 	var p dnsv1alpha1.Provider
 	err = r.Get(ctx, client.ObjectKey{Namespace: rr.Namespace, Name: rr.Spec.ProviderRef}, &p)
-	if errors.IsNotFound(err) {
-		return ctrl.Result{}, nil
+	if apierrors.IsNotFound(err) {
+		return ctrl.Result{}, r.setNotReady(ctx, &rr, "ProviderNotFound", fmt.Sprintf("provider %s not found", rr.Spec.ProviderRef))
 	}
 	if err != nil {
 		logger.Error(err, "unable to get ResourceRecord", "name", rr.Namespace+"/"+rr.Spec.ProviderRef)
@@ -96,19 +122,291 @@ func (r *ResourceRecordReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	}
 
 	// setup client
-	var route53 provider.Route53Provider
-	client, err := route53.NewClient(ctx, &p, r.Client)
+	dnsProvider, err := provider.New(ctx, &p, r.Client)
 	if err != nil {
 		logger.Error(err, "failed initialize client")
+		return syncFailed(err, r.setNotReady(ctx, &rr, "ClientError", err.Error()))
+	}
+
+	// while the last change of the spec propagates, only its status is polled,
+	// so that waiting records do not list their records every poll. Changes of
+	// the owner are converged once it has propagated.
+	if propagationPending(&rr) {
+		if result := r.checkPropagation(ctx, dnsProvider, &rr); result.RequeueAfter != 0 {
+			return result, r.Status().Update(ctx, &rr)
+		}
+	}
+
+	// resolve the referenced cidr collection
+	var refs provider.Refs
+	if cr := rr.Spec.CidrRouting; cr != nil {
+		var collection dnsv1alpha1.CidrCollection
+		err = r.Get(ctx, client.ObjectKey{Namespace: rr.Namespace, Name: cr.CollectionRef}, &collection)
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, r.setNotReady(ctx, &rr, "CidrCollectionNotFound", fmt.Sprintf("cidr collection %s not found", cr.CollectionRef))
+		}
+		if err != nil {
+			logger.Error(err, "unable to get CidrCollection", "name", rr.Namespace+"/"+cr.CollectionRef)
+			return ctrl.Result{}, err
+		}
+		if collection.Status.CollectionID == "" {
+			return ctrl.Result{}, r.setNotReady(ctx, &rr, "CidrCollectionNotReady", fmt.Sprintf("cidr collection %s is not created yet", cr.CollectionRef))
+		}
+		refs.CidrCollectionID = collection.Status.CollectionID
+	}
+
+	// resolve the referenced health check
+	if name := rr.Spec.HealthCheckRef; name != "" {
+		var hc dnsv1alpha1.HealthCheck
+		err = r.Get(ctx, client.ObjectKey{Namespace: rr.Namespace, Name: name}, &hc)
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, r.setNotReady(ctx, &rr, "HealthCheckNotFound", fmt.Sprintf("health check %s not found", name))
+		}
+		if err != nil {
+			logger.Error(err, "unable to get HealthCheck", "name", rr.Namespace+"/"+name)
+			return ctrl.Result{}, err
+		}
+		if hc.Status.HealthCheckID == "" {
+			return ctrl.Result{}, r.setNotReady(ctx, &rr, "HealthCheckNotReady", fmt.Sprintf("health check %s is not created yet", name))
+		}
+		refs.HealthCheckID = hc.Status.HealthCheckID
 	}
 
 	// converge
-	err = client.Converge(ctx, p.Spec.Route53.HostedZoneID, p.Spec.Route53.HostedZoneName, owner.Spec.Names, rr.Spec)
-	if err != nil {
+	fqdns, changeId, err := provider.Converge(ctx, dnsProvider, r.recordOwnerId(&rr), owner.Spec.Names, rr.Spec, refs, rr.Status.FQDNs, rr.Annotations[dnsv1alpha1.AdoptAnnotation] == "true")
+	var conflict *provider.ConflictError
+	if err != nil && !errors.As(err, &conflict) {
 		logger.Error(err, "failed converge")
+		return syncFailed(err, r.setNotReady(ctx, &rr, "ConvergeFailed", err.Error()))
+	}
+
+	// remember managed names to prune them when they are removed from owner
+	rr.Status.FQDNs = fqdns
+	if changeId != "" {
+		changeSubmitted(&rr, changeId)
+	}
+	if conflict != nil {
+		logger.Info("records owned by others are left untouched", "fqdns", conflict.FQDNs)
+		return syncFailed(conflict, r.setNotReady(ctx, &rr, "OwnershipConflict", conflict.Error()))
+	}
+	now := metav1.Now()
+	rr.Status.LastSyncTime = &now
+	setSyncedCondition(&rr, metav1.ConditionTrue, "Synced", "records are in sync with provider")
+
+	// the records of a routing policy may be created in any order, so a
+	// missing sibling only keeps the record from being ready
+	incomplete, err := r.incompleteRoutingPolicy(ctx, &rr)
+	if err != nil {
+		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	// wait for the change to propagate without blocking the worker
+	result := r.checkPropagation(ctx, dnsProvider, &rr)
+	setReadyCondition(&rr, incomplete)
+	if result.RequeueAfter == 0 {
+		result.RequeueAfter = resyncInterval
+	}
+	if err := r.Status().Update(ctx, &rr); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return result, nil
+}
+
+// changeSubmitted starts tracking the propagation of the submitted change.
+func changeSubmitted(rr *dnsv1alpha1.ResourceRecord, changeId string) {
+	now := metav1.Now()
+	rr.Status.ChangeID = changeId
+	rr.Status.ChangeSubmitTime = &now
+	rr.Status.PropagationDuration = nil
+	setPropagatedCondition(rr, metav1.ConditionFalse, "Pending", fmt.Sprintf("change %s is pending", changeId))
+}
+
+// propagationPending reports whether the records of the current generation
+// are synced and the change submitted for them has not propagated yet.
+func propagationPending(rr *dnsv1alpha1.ResourceRecord) bool {
+	return rr.Status.ChangeID != "" &&
+		rr.Status.ObservedGeneration == rr.Generation &&
+		meta.IsStatusConditionTrue(rr.Status.Conditions, dnsv1alpha1.ResourceRecordConditionSynced) &&
+		!meta.IsStatusConditionTrue(rr.Status.Conditions, dnsv1alpha1.ResourceRecordConditionPropagated)
+}
+
+// checkPropagation polls the status of the pending change and requeues the
+// request until the change has propagated.
+func (r *ResourceRecordReconciler) checkPropagation(ctx context.Context, dnsProvider provider.DNSProvider, rr *dnsv1alpha1.ResourceRecord) ctrl.Result {
+	logger := log.FromContext(ctx)
+
+	if rr.Status.ChangeID == "" || meta.IsStatusConditionTrue(rr.Status.Conditions, dnsv1alpha1.ResourceRecordConditionPropagated) {
+		return ctrl.Result{}
+	}
+
+	inSync, err := dnsProvider.ChangeInSync(ctx, rr.Status.ChangeID)
+	if err != nil {
+		logger.Error(err, "failed get change status", "changeID", rr.Status.ChangeID)
+		setPropagatedCondition(rr, metav1.ConditionUnknown, "PollFailed", err.Error())
+		if provider.IsTerminal(err) {
+			return ctrl.Result{}
+		}
+		return ctrl.Result{RequeueAfter: propagationPollInterval}
+	}
+	if !inSync {
+		setPropagatedCondition(rr, metav1.ConditionFalse, "Pending", fmt.Sprintf("change %s is pending", rr.Status.ChangeID))
+		return ctrl.Result{RequeueAfter: propagationPollInterval}
+	}
+
+	if rr.Status.ChangeSubmitTime != nil {
+		rr.Status.PropagationDuration = &metav1.Duration{Duration: time.Since(rr.Status.ChangeSubmitTime.Time).Round(time.Second)}
+	}
+	setPropagatedCondition(rr, metav1.ConditionTrue, "InSync", fmt.Sprintf("change %s has propagated", rr.Status.ChangeID))
+	return ctrl.Result{}
+}
+
+func setPropagatedCondition(rr *dnsv1alpha1.ResourceRecord, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&rr.Status.Conditions, metav1.Condition{
+		Type:               dnsv1alpha1.ResourceRecordConditionPropagated,
+		Status:             status,
+		ObservedGeneration: rr.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// recordOwnerId identifies the controller instance and the ResourceRecord in owner TXT records.
+func (r *ResourceRecordReconciler) recordOwnerId(rr *dnsv1alpha1.ResourceRecord) string {
+	return fmt.Sprintf("%s/%s/%s", r.OwnerID, rr.Namespace, rr.Name)
+}
+
+// setNotReady records the reason why the records could not be synced.
+func (r *ResourceRecordReconciler) setNotReady(ctx context.Context, rr *dnsv1alpha1.ResourceRecord, reason, message string) error {
+	setSyncedCondition(rr, metav1.ConditionFalse, reason, message)
+	setReadyCondition(rr, "")
+	return r.Status().Update(ctx, rr)
+}
+
+func setSyncedCondition(rr *dnsv1alpha1.ResourceRecord, status metav1.ConditionStatus, reason, message string) {
+	setCondition(&rr.Status.Conditions, &rr.Status.ObservedGeneration, rr.Generation, dnsv1alpha1.ResourceRecordConditionSynced, status, reason, message)
+}
+
+// setReadyCondition derives Ready from the other conditions: the records are
+// ready once they are synced, the last change has propagated and the routing
+// policy is not incomplete, which is described by incomplete otherwise.
+func setReadyCondition(rr *dnsv1alpha1.ResourceRecord, incomplete string) {
+	status, reason, message := metav1.ConditionTrue, "Ready", "records are in sync and served by provider"
+	synced := meta.FindStatusCondition(rr.Status.Conditions, dnsv1alpha1.ResourceRecordConditionSynced)
+	propagated := meta.FindStatusCondition(rr.Status.Conditions, dnsv1alpha1.ResourceRecordConditionPropagated)
+	switch {
+	case synced == nil || synced.Status != metav1.ConditionTrue:
+		status = metav1.ConditionFalse
+		if synced != nil {
+			reason, message = synced.Reason, synced.Message
+		}
+	case incomplete != "":
+		status, reason, message = metav1.ConditionFalse, "IncompleteRoutingPolicy", incomplete
+	case propagated != nil && propagated.Status != metav1.ConditionTrue:
+		status, reason, message = metav1.ConditionFalse, "PropagationPending", propagated.Message
+	}
+	setCondition(&rr.Status.Conditions, &rr.Status.ObservedGeneration, rr.Generation, dnsv1alpha1.ResourceRecordConditionReady, status, reason, message)
+}
+
+// siblings returns the other ResourceRecords which write records of the same
+// class for the same owner and are not being deleted.
+func (r *ResourceRecordReconciler) siblings(ctx context.Context, rr *dnsv1alpha1.ResourceRecord) ([]dnsv1alpha1.ResourceRecord, error) {
+	var list dnsv1alpha1.ResourceRecordList
+	if err := r.List(ctx, &list, client.InNamespace(rr.Namespace), client.MatchingFields{ownerField: rr.Spec.OwnerRef}); err != nil {
+		return nil, err
+	}
+
+	var siblings []dnsv1alpha1.ResourceRecord
+	for _, item := range list.Items {
+		if item.Name == rr.Name || !item.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if item.Spec.ProviderRef == rr.Spec.ProviderRef && item.Spec.Class == rr.Spec.Class {
+			siblings = append(siblings, item)
+		}
+	}
+	return siblings, nil
+}
+
+// incompleteRoutingPolicy describes the record missing from the routing
+// policy of rr, i.e. the other record of a failover pair or the default
+// location of geolocation records. It is empty when the policy is complete.
+func (r *ResourceRecordReconciler) incompleteRoutingPolicy(ctx context.Context, rr *dnsv1alpha1.ResourceRecord) (string, error) {
+	if rr.Spec.Failover == nil && (rr.Spec.GeoLocation == nil || rr.Spec.GeoLocation.IsDefault()) {
+		return "", nil
+	}
+	siblings, err := r.siblings(ctx, rr)
+	if err != nil {
+		return "", err
+	}
+
+	if rr.Spec.Failover != nil {
+		want := dnsv1alpha1.FailoverSecondary
+		if rr.Spec.Failover.Type == dnsv1alpha1.FailoverSecondary {
+			want = dnsv1alpha1.FailoverPrimary
+		}
+		for _, s := range siblings {
+			if s.Spec.Failover != nil && s.Spec.Failover.Type == want {
+				return "", nil
+			}
+		}
+		return fmt.Sprintf("the %s record of the failover pair is missing", want), nil
+	}
+
+	for _, s := range siblings {
+		if s.Spec.GeoLocation != nil && s.Spec.GeoLocation.IsDefault() {
+			return "", nil
+		}
+	}
+	return fmt.Sprintf("the geolocation record of the default location %q is missing", dnsv1alpha1.GeoLocationDefault), nil
+}
+
+func (r *ResourceRecordReconciler) reconcileDelete(ctx context.Context, rr *dnsv1alpha1.ResourceRecord) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(rr, finalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	if rr.Spec.DeletionPolicy != dnsv1alpha1.DeletionPolicyRetain {
+		if err := r.deleteRecords(ctx, rr); err != nil {
+			logger.Error(err, "failed delete records")
+			if serr := r.setNotReady(ctx, rr, "DeleteFailed", err.Error()); serr != nil {
+				logger.Error(serr, "unable to update ResourceRecord status")
+			}
+			return ctrl.Result{}, err
+		}
+	}
+
+	return removeFinalizer(ctx, r, rr)
+}
+
+func (r *ResourceRecordReconciler) deleteRecords(ctx context.Context, rr *dnsv1alpha1.ResourceRecord) error {
+	// get owner object. the names recorded in status are deleted even if the owner is gone.
+	var owner dnsv1alpha1.Owner
+	err := r.Get(ctx, client.ObjectKey{Namespace: rr.Namespace, Name: rr.Spec.OwnerRef}, &owner)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	// get provider object
+	var p dnsv1alpha1.Provider
+	err = r.Get(ctx, client.ObjectKey{Namespace: rr.Namespace, Name: rr.Spec.ProviderRef}, &p)
+	if apierrors.IsNotFound(err) {
+		// the provider is kept while records reference it, so it was removed by force
+		return &provider.TerminalError{Err: fmt.Errorf("provider %s not found, set deletionPolicy to %s to leave the records", rr.Spec.ProviderRef, dnsv1alpha1.DeletionPolicyRetain)}
+	}
+	if err != nil {
+		return err
+	}
+
+	// setup client
+	dnsProvider, err := provider.New(ctx, &p, r.Client)
+	if err != nil {
+		return err
+	}
+
+	return provider.Delete(ctx, dnsProvider, r.recordOwnerId(rr), owner.Spec.Names, rr.Spec, rr.Status.FQDNs)
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -122,22 +420,106 @@ func (r *ResourceRecordReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	}); err != nil {
 		return err
 	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &dnsv1alpha1.ResourceRecord{}, providerField, func(rawObj client.Object) []string {
+		rr := rawObj.(*dnsv1alpha1.ResourceRecord)
+		if rr.Spec.ProviderRef == "" {
+			return nil
+		}
+		return []string{rr.Spec.ProviderRef}
+	}); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &dnsv1alpha1.ResourceRecord{}, cidrCollectionField, func(rawObj client.Object) []string {
+		rr := rawObj.(*dnsv1alpha1.ResourceRecord)
+		if rr.Spec.CidrRouting == nil {
+			return nil
+		}
+		return []string{rr.Spec.CidrRouting.CollectionRef}
+	}); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &dnsv1alpha1.ResourceRecord{}, healthCheckField, func(rawObj client.Object) []string {
+		rr := rawObj.(*dnsv1alpha1.ResourceRecord)
+		if rr.Spec.HealthCheckRef == "" {
+			return nil
+		}
+		return []string{rr.Spec.HealthCheckRef}
+	}); err != nil {
+		return err
+	}
 
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&dnsv1alpha1.ResourceRecord{}).
+		For(&dnsv1alpha1.ResourceRecord{}, builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}))).
+		Watches(
+			&source.Kind{Type: &dnsv1alpha1.ResourceRecord{}},
+			handler.EnqueueRequestsFromMapFunc(r.findSiblings),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
 		Watches(
 			&source.Kind{Type: &dnsv1alpha1.Owner{}},
 			handler.EnqueueRequestsFromMapFunc(r.findObjectsForOwner),
 			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
 		).
+		Watches(
+			&source.Kind{Type: &dnsv1alpha1.Provider{}},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsForProvider),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
+		Watches(
+			&source.Kind{Type: &dnsv1alpha1.CidrCollection{}},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsForCidrCollection),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		).
+		Watches(
+			&source.Kind{Type: &dnsv1alpha1.HealthCheck{}},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsForHealthCheck),
+			// the status of a health check is refreshed periodically, only its ID matters
+			builder.WithPredicates(predicate.Funcs{UpdateFunc: func(e event.UpdateEvent) bool {
+				return e.ObjectOld.(*dnsv1alpha1.HealthCheck).Status.HealthCheckID != e.ObjectNew.(*dnsv1alpha1.HealthCheck).Status.HealthCheckID
+			}}),
+		).
 		Complete(r)
 }
 
+// findSiblings returns the requests of the other ResourceRecords of the owner
+// of rr, whose routing policy may be completed or broken by rr.
+func (r *ResourceRecordReconciler) findSiblings(rr client.Object) []reconcile.Request {
+	var requests []reconcile.Request
+	for _, req := range r.findObjectsByValue(ownerField, rr.GetNamespace(), rr.(*dnsv1alpha1.ResourceRecord).Spec.OwnerRef) {
+		if req.Name != rr.GetName() {
+			requests = append(requests, req)
+		}
+	}
+	return requests
+}
+
 func (r *ResourceRecordReconciler) findObjectsForOwner(owner client.Object) []reconcile.Request {
+	return r.findObjectsByField(ownerField, owner)
+}
+
+func (r *ResourceRecordReconciler) findObjectsForProvider(p client.Object) []reconcile.Request {
+	return r.findObjectsByField(providerField, p)
+}
+
+func (r *ResourceRecordReconciler) findObjectsForCidrCollection(collection client.Object) []reconcile.Request {
+	return r.findObjectsByField(cidrCollectionField, collection)
+}
+
+func (r *ResourceRecordReconciler) findObjectsForHealthCheck(hc client.Object) []reconcile.Request {
+	return r.findObjectsByField(healthCheckField, hc)
+}
+
+// findObjectsByField returns the requests of the ResourceRecords which refer to obj by field.
+func (r *ResourceRecordReconciler) findObjectsByField(field string, obj client.Object) []reconcile.Request {
+	return r.findObjectsByValue(field, obj.GetNamespace(), obj.GetName())
+}
+
+// findObjectsByValue returns the requests of the ResourceRecords in the namespace whose field is value.
+func (r *ResourceRecordReconciler) findObjectsByValue(field, namespace, value string) []reconcile.Request {
 	attachedResourceRecords := &dnsv1alpha1.ResourceRecordList{}
 	listOps := &client.ListOptions{
-		FieldSelector: fields.OneTermEqualSelector(ownerField, owner.GetName()),
-		Namespace:     owner.GetNamespace(),
+		FieldSelector: fields.OneTermEqualSelector(field, value),
+		Namespace:     namespace,
 	}
 	err := r.List(context.TODO(), attachedResourceRecords, listOps)
 	if err != nil {
@@ -0,0 +1,238 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	dnsv1alpha1 "github.com/ch1aki/dns-rr/api/v1alpha1"
+	"github.com/ch1aki/dns-rr/controllers/provider"
+)
+
+// changeStatusProvider reports the status of every change as inSync or err.
+type changeStatusProvider struct {
+	provider.DNSProvider
+	inSync bool
+	err    error
+}
+
+func (p *changeStatusProvider) ChangeInSync(ctx context.Context, changeId string) (bool, error) {
+	return p.inSync, p.err
+}
+
+func condition(conditionType string, status metav1.ConditionStatus, reason, message string) metav1.Condition {
+	return metav1.Condition{Type: conditionType, Status: status, ObservedGeneration: 2, Reason: reason, Message: message}
+}
+
+func TestPropagationPending(t *testing.T) {
+	synced := condition(dnsv1alpha1.ResourceRecordConditionSynced, metav1.ConditionTrue, "Synced", "")
+	notSynced := condition(dnsv1alpha1.ResourceRecordConditionSynced, metav1.ConditionFalse, "SyncFailed", "")
+	pending := condition(dnsv1alpha1.ResourceRecordConditionPropagated, metav1.ConditionFalse, "Pending", "")
+	propagated := condition(dnsv1alpha1.ResourceRecordConditionPropagated, metav1.ConditionTrue, "InSync", "")
+
+	tests := []struct {
+		name   string
+		status dnsv1alpha1.ResourceRecordStatus
+		want   bool
+	}{
+		{
+			name:   "no change submitted",
+			status: dnsv1alpha1.ResourceRecordStatus{ObservedGeneration: 2, Conditions: []metav1.Condition{synced}},
+			want:   false,
+		},
+		{
+			name:   "change pending",
+			status: dnsv1alpha1.ResourceRecordStatus{ChangeID: "C1", ObservedGeneration: 2, Conditions: []metav1.Condition{synced, pending}},
+			want:   true,
+		},
+		{
+			name:   "change propagated",
+			status: dnsv1alpha1.ResourceRecordStatus{ChangeID: "C1", ObservedGeneration: 2, Conditions: []metav1.Condition{synced, propagated}},
+			want:   false,
+		},
+		{
+			name:   "spec changed since the change",
+			status: dnsv1alpha1.ResourceRecordStatus{ChangeID: "C1", ObservedGeneration: 1, Conditions: []metav1.Condition{synced, pending}},
+			want:   false,
+		},
+		{
+			name:   "last sync failed",
+			status: dnsv1alpha1.ResourceRecordStatus{ChangeID: "C1", ObservedGeneration: 2, Conditions: []metav1.Condition{notSynced, pending}},
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := &dnsv1alpha1.ResourceRecord{ObjectMeta: metav1.ObjectMeta{Generation: 2}, Status: tt.status}
+			if got := propagationPending(rr); got != tt.want {
+				t.Errorf("propagationPending() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckPropagation(t *testing.T) {
+	propagated := condition(dnsv1alpha1.ResourceRecordConditionPropagated, metav1.ConditionTrue, "InSync", "change C1 has propagated")
+
+	tests := []struct {
+		name       string
+		changeId   string
+		conditions []metav1.Condition
+		provider   *changeStatusProvider
+		want       ctrl.Result
+		wantStatus metav1.ConditionStatus
+		wantReason string
+	}{
+		{
+			name:     "no change submitted",
+			provider: &changeStatusProvider{},
+			want:     ctrl.Result{},
+		},
+		{
+			name:       "already propagated",
+			changeId:   "C1",
+			conditions: []metav1.Condition{propagated},
+			provider:   &changeStatusProvider{err: errors.New("must not be polled")},
+			want:       ctrl.Result{},
+			wantStatus: metav1.ConditionTrue,
+			wantReason: "InSync",
+		},
+		{
+			name:       "pending",
+			changeId:   "C1",
+			provider:   &changeStatusProvider{inSync: false},
+			want:       ctrl.Result{RequeueAfter: propagationPollInterval},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: "Pending",
+		},
+		{
+			name:       "in sync",
+			changeId:   "C1",
+			provider:   &changeStatusProvider{inSync: true},
+			want:       ctrl.Result{},
+			wantStatus: metav1.ConditionTrue,
+			wantReason: "InSync",
+		},
+		{
+			name:       "retryable poll error",
+			changeId:   "C1",
+			provider:   &changeStatusProvider{err: errors.New("throttled")},
+			want:       ctrl.Result{RequeueAfter: propagationPollInterval},
+			wantStatus: metav1.ConditionUnknown,
+			wantReason: "PollFailed",
+		},
+		{
+			name:       "terminal poll error",
+			changeId:   "C1",
+			provider:   &changeStatusProvider{err: &provider.TerminalError{Err: errors.New("no such change")}},
+			want:       ctrl.Result{},
+			wantStatus: metav1.ConditionUnknown,
+			wantReason: "PollFailed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := &dnsv1alpha1.ResourceRecord{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Status:     dnsv1alpha1.ResourceRecordStatus{ChangeID: tt.changeId, Conditions: tt.conditions},
+			}
+			r := &ResourceRecordReconciler{}
+			if got := r.checkPropagation(context.TODO(), tt.provider, rr); got != tt.want {
+				t.Errorf("checkPropagation() = %v, want %v", got, tt.want)
+			}
+
+			c := meta.FindStatusCondition(rr.Status.Conditions, dnsv1alpha1.ResourceRecordConditionPropagated)
+			if tt.wantReason == "" {
+				if c != nil {
+					t.Errorf("Propagated = %v, want none", c)
+				}
+				return
+			}
+			if c == nil || c.Status != tt.wantStatus || c.Reason != tt.wantReason {
+				t.Errorf("Propagated = %v, want %s %s", c, tt.wantStatus, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestSetReadyCondition(t *testing.T) {
+	synced := condition(dnsv1alpha1.ResourceRecordConditionSynced, metav1.ConditionTrue, "Synced", "records are in sync")
+	notSynced := condition(dnsv1alpha1.ResourceRecordConditionSynced, metav1.ConditionFalse, "ProviderNotFound", "provider p not found")
+	pending := condition(dnsv1alpha1.ResourceRecordConditionPropagated, metav1.ConditionFalse, "Pending", "change C1 is pending")
+	propagated := condition(dnsv1alpha1.ResourceRecordConditionPropagated, metav1.ConditionTrue, "InSync", "change C1 has propagated")
+
+	tests := []struct {
+		name       string
+		conditions []metav1.Condition
+		incomplete string
+		want       metav1.Condition
+	}{
+		{
+			name:       "synced without change",
+			conditions: []metav1.Condition{synced},
+			want:       condition(dnsv1alpha1.ResourceRecordConditionReady, metav1.ConditionTrue, "Ready", "records are in sync and served by provider"),
+		},
+		{
+			name:       "synced and propagated",
+			conditions: []metav1.Condition{synced, propagated},
+			want:       condition(dnsv1alpha1.ResourceRecordConditionReady, metav1.ConditionTrue, "Ready", "records are in sync and served by provider"),
+		},
+		{
+			name:       "not synced",
+			conditions: []metav1.Condition{notSynced, propagated},
+			want:       condition(dnsv1alpha1.ResourceRecordConditionReady, metav1.ConditionFalse, "ProviderNotFound", "provider p not found"),
+		},
+		{
+			name:       "incomplete routing policy",
+			conditions: []metav1.Condition{synced, pending},
+			incomplete: "no SECONDARY record for the failover",
+			want:       condition(dnsv1alpha1.ResourceRecordConditionReady, metav1.ConditionFalse, "IncompleteRoutingPolicy", "no SECONDARY record for the failover"),
+		},
+		{
+			name:       "propagation pending",
+			conditions: []metav1.Condition{synced, pending},
+			want:       condition(dnsv1alpha1.ResourceRecordConditionReady, metav1.ConditionFalse, "PropagationPending", "change C1 is pending"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := &dnsv1alpha1.ResourceRecord{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Status:     dnsv1alpha1.ResourceRecordStatus{ObservedGeneration: 1, Conditions: tt.conditions},
+			}
+			setReadyCondition(rr, tt.incomplete)
+
+			got := meta.FindStatusCondition(rr.Status.Conditions, dnsv1alpha1.ResourceRecordConditionReady)
+			if got == nil {
+				t.Fatalf("Ready is not set")
+			}
+			got.LastTransitionTime = metav1.Time{}
+			if *got != tt.want {
+				t.Errorf("Ready = %v, want %v", *got, tt.want)
+			}
+			if rr.Status.ObservedGeneration != 2 {
+				t.Errorf("observedGeneration = %d, want 2", rr.Status.ObservedGeneration)
+			}
+		})
+	}
+}
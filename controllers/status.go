@@ -0,0 +1,77 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/ch1aki/dns-rr/controllers/provider"
+)
+
+// finalizer keeps the objects of every kind of the group until the controller
+// has removed what it created in the provider for them.
+const finalizer = "dns.ch1aki.github.io/finalizer"
+
+// addFinalizer adds the finalizer to obj unless it is already there.
+func addFinalizer(ctx context.Context, c client.Writer, obj client.Object) error {
+	if controllerutil.ContainsFinalizer(obj, finalizer) {
+		return nil
+	}
+	controllerutil.AddFinalizer(obj, finalizer)
+	return c.Update(ctx, obj)
+}
+
+// removeFinalizer lets obj be deleted once it is cleaned up in the provider.
+func removeFinalizer(ctx context.Context, c client.Writer, obj client.Object) (ctrl.Result, error) {
+	controllerutil.RemoveFinalizer(obj, finalizer)
+	if err := c.Update(ctx, obj); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// setCondition records a condition observed at generation in conditions.
+func setCondition(conditions *[]metav1.Condition, observedGeneration *int64, generation int64, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	*observedGeneration = generation
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// syncFailed returns the result of a sync which failed with err, once err is
+// recorded in status with serr as the result. Retryable errors are returned so
+// that the request is requeued with backoff, terminal errors wait for the next
+// change.
+func syncFailed(err, serr error) (ctrl.Result, error) {
+	if serr != nil {
+		return ctrl.Result{}, serr
+	}
+	if provider.IsTerminal(err) {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{}, err
+}
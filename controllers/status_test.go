@@ -0,0 +1,104 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/ch1aki/dns-rr/controllers/provider"
+)
+
+func TestSyncFailed(t *testing.T) {
+	retryable := errors.New("throttled")
+	terminal := &provider.TerminalError{Err: errors.New("invalid rdata")}
+	conflict := errors.New("status update conflict")
+
+	tests := []struct {
+		name    string
+		err     error
+		serr    error
+		wantErr error
+	}{
+		{
+			name:    "retryable error is requeued with backoff",
+			err:     retryable,
+			wantErr: retryable,
+		},
+		{
+			name: "terminal error waits for the next change",
+			err:  terminal,
+		},
+		{
+			name: "wrapped terminal error waits for the next change",
+			err:  fmt.Errorf("failed to apply changes: %w", terminal),
+		},
+		{
+			name: "rejected change batch waits for the next change",
+			err:  &types.InvalidChangeBatch{Message: new(string)},
+		},
+		{
+			name:    "failed status update is requeued",
+			err:     terminal,
+			serr:    conflict,
+			wantErr: conflict,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := syncFailed(tt.err, tt.serr)
+			if got != (ctrl.Result{}) {
+				t.Errorf("syncFailed() = %v, want no result", got)
+			}
+			if err != tt.wantErr {
+				t.Errorf("syncFailed() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetCondition(t *testing.T) {
+	var (
+		conditions         []metav1.Condition
+		observedGeneration int64
+	)
+	setCondition(&conditions, &observedGeneration, 1, "Synced", metav1.ConditionFalse, "SyncFailed", "throttled")
+	first := meta.FindStatusCondition(conditions, "Synced").LastTransitionTime
+
+	setCondition(&conditions, &observedGeneration, 2, "Synced", metav1.ConditionFalse, "SyncFailed", "invalid rdata")
+	setCondition(&conditions, &observedGeneration, 2, "Ready", metav1.ConditionFalse, "SyncFailed", "invalid rdata")
+
+	if observedGeneration != 2 {
+		t.Errorf("observedGeneration = %d, want 2", observedGeneration)
+	}
+	if len(conditions) != 2 {
+		t.Fatalf("conditions = %v, want Synced and Ready", conditions)
+	}
+	synced := meta.FindStatusCondition(conditions, "Synced")
+	if synced.ObservedGeneration != 2 || synced.Message != "invalid rdata" {
+		t.Errorf("Synced = %v, want observed at 2 with the latest message", synced)
+	}
+	if !synced.LastTransitionTime.Equal(&first) {
+		t.Errorf("lastTransitionTime = %v, want %v as the status did not change", synced.LastTransitionTime, first)
+	}
+}
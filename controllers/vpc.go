@@ -0,0 +1,45 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dnsv1alpha1 "github.com/ch1aki/dns-rr/api/v1alpha1"
+	"github.com/ch1aki/dns-rr/controllers/provider"
+)
+
+// vpcAssociations builds the backends of the accounts which own the VPCs in
+// other accounts from the credentials of the VPCs.
+func vpcAssociations(ctx context.Context, c client.Client, namespace string, vpcs []dnsv1alpha1.VPC) ([]provider.VPCAssociation, error) {
+	associations := make([]provider.VPCAssociation, len(vpcs))
+	for i, vpc := range vpcs {
+		associations[i].VPC = vpc
+		if vpc.Auth == nil {
+			continue
+		}
+
+		owner, err := provider.NewVPCOwner(ctx, namespace, vpc, c)
+		if err != nil {
+			return nil, err
+		}
+		associations[i].Owner = owner
+	}
+	return associations, nil
+}